@@ -0,0 +1,189 @@
+package excelstructure
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// columnResolver resolve the header text to the struct column by the column name and aliases
+type columnResolver struct {
+	// exact header text to column
+	exact map[string]string
+	// normalized header text to column, nil if normalization is not enabled
+	normalized map[string]string
+	// positions column index start with 1 to column, bound by col or index tag
+	positions map[int]string
+	// repeats the column templates of repeat fields
+	repeats []repeatMatcher
+}
+
+// repeatMatcher match the header text to the column template of repeat field
+type repeatMatcher struct {
+	template string
+	exact    *regexp.Regexp
+	// normalized nil if normalization is not enabled
+	normalized *regexp.Regexp
+}
+
+// newColumnResolver create resolver of the struct tag settings, exact match takes priority over normalized match.
+// the fields are added in declaration order, the column names take priority over the aliases,
+// and the first field wins when fields share a name
+func newColumnResolver(structType reflect.Type, tagMap map[string]TagSetting, normalize bool) *columnResolver {
+	r := &columnResolver{exact: make(map[string]string), positions: make(map[int]string)}
+	if normalize {
+		r.normalized = make(map[string]string)
+	}
+	var tagSettings []TagSetting
+	for _, field := range structFields(structType, tagMap) {
+		tagSetting, ok := tagMap[field.Name]
+		if !ok || tagSetting.Skip || tagSetting.Inline || tagSetting.Extra {
+			continue
+		}
+		// 按位置绑定的列不匹配表头文本
+		if tagSetting.ColIndex > 0 {
+			if _, ok := r.positions[tagSetting.ColIndex]; !ok {
+				r.positions[tagSetting.ColIndex] = tagSetting.Column
+			}
+			continue
+		}
+		if tagSetting.Repeat {
+			r.addRepeat(tagSetting, normalize)
+			continue
+		}
+		tagSettings = append(tagSettings, tagSetting)
+	}
+
+	for _, tagSetting := range tagSettings {
+		r.add(tagSetting.Column, tagSetting.Column)
+	}
+	for _, tagSetting := range tagSettings {
+		for _, alias := range tagSetting.Aliases {
+			r.add(alias, tagSetting.Column)
+		}
+	}
+	return r
+}
+
+// add resolve the header name to the column, the name already added is not overwritten
+func (r *columnResolver) add(name, column string) {
+	if _, ok := r.exact[name]; !ok {
+		r.exact[name] = column
+	}
+	if r.normalized == nil {
+		return
+	}
+	if _, ok := r.normalized[NormalizeColumnName(name)]; !ok {
+		r.normalized[NormalizeColumnName(name)] = column
+	}
+}
+
+// addRepeat add the column templates of the repeat field, the matched header is resolved to the numbered column
+func (r *columnResolver) addRepeat(tagSetting TagSetting, normalize bool) {
+	for _, name := range append([]string{tagSetting.Column}, tagSetting.Aliases...) {
+		if !isRepeatColumn(name) {
+			continue
+		}
+		m := repeatMatcher{template: tagSetting.Column, exact: repeatPattern(name)}
+		if normalize {
+			m.normalized = repeatPattern(NormalizeColumnName(name))
+		}
+		r.repeats = append(r.repeats, m)
+	}
+}
+
+// resolve the struct column of the header text, false if not matched
+func (r *columnResolver) resolve(header string) (string, bool) {
+	if column, ok := r.exact[header]; ok {
+		return column, true
+	}
+	for _, m := range r.repeats {
+		if n, ok := matchRepeatColumn(m.exact, header); ok {
+			return repeatColumnName(m.template, n), true
+		}
+	}
+	if r.normalized != nil {
+		if column, ok := r.normalized[NormalizeColumnName(header)]; ok {
+			return column, true
+		}
+		for _, m := range r.repeats {
+			if n, ok := matchRepeatColumn(m.normalized, NormalizeColumnName(header)); ok {
+				return repeatColumnName(m.template, n), true
+			}
+		}
+	}
+	return "", false
+}
+
+// resolveHeader replace the matched header texts with the struct columns, unmatched texts are kept.
+// the columns bound by position replace the header texts at the position whatever the texts are
+func (r *columnResolver) resolveHeader(header []string) []string {
+	size := len(header)
+	for colIndex := range r.positions {
+		if colIndex > size {
+			size = colIndex
+		}
+	}
+
+	resolved := make([]string, size)
+	for i := range resolved {
+		if column, ok := r.positions[i+1]; ok {
+			resolved[i] = column
+			continue
+		}
+		if i >= len(header) {
+			continue
+		}
+		if column, ok := r.resolve(header[i]); ok {
+			resolved[i] = column
+		} else {
+			resolved[i] = header[i]
+		}
+	}
+	return resolved
+}
+
+// columnLetters the column letters A, B, C... of the first n columns, used as header of the sheet without header
+func columnLetters(n int) []string {
+	letters := make([]string, 0, n)
+	for i := 1; i <= n; i++ {
+		letter, err := excelize.ColumnNumberToName(i)
+		if err != nil {
+			break
+		}
+		letters = append(letters, letter)
+	}
+	return letters
+}
+
+// maxRowLength the max cell count of the rows
+func maxRowLength(rows [][]string) int {
+	n := 0
+	for _, row := range rows {
+		if len(row) > n {
+			n = len(row)
+		}
+	}
+	return n
+}
+
+// NormalizeColumnName 表头归一化，全角转半角，转小写，去掉空白和下划线，如" User_Name "为"username"
+func NormalizeColumnName(name string) string {
+	var sb strings.Builder
+	for _, r := range name {
+		switch {
+		case r == '　':
+			r = ' '
+		case r >= '！' && r <= '～':
+			r -= 0xfee0
+		}
+		if unicode.IsSpace(r) || r == '_' {
+			continue
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
@@ -0,0 +1,195 @@
+package excelstructure
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+type Partner struct {
+	UserName string `excel:"column:user_name|用户名|User Name"`
+	Age      int    `excel:"column:age|年龄"`
+}
+
+func Test_NormalizeColumnName(t *testing.T) {
+	require.Equal(t, "username", NormalizeColumnName(" User_Name "))
+	require.Equal(t, "username", NormalizeColumnName("ＵＳＥＲ　ＮＡＭＥ"))
+	require.Equal(t, "用户名", NormalizeColumnName("用户 名"))
+}
+
+func Test_ReadColumnAlias(t *testing.T) {
+	p := NewParser()
+	p.StrictHeader = true
+	var partners []Partner
+	err := p.Read(newSheetFile(t, [][]string{
+		{"用户名", "年龄"},
+		{"booyang", "18"},
+	}), &partners)
+	require.NoError(t, err)
+	require.Equal(t, []Partner{{UserName: "booyang", Age: 18}}, partners)
+
+	// normalization is opt-in
+	fileName := newSheetFile(t, [][]string{
+		{" username ", "ＡＧＥ"},
+		{"booyang", "18"},
+	})
+	err = p.Read(fileName, &partners)
+	require.Error(t, err)
+
+	p.NormalizeHeader = true
+	err = p.Read(fileName, &partners)
+	require.NoError(t, err)
+	require.Equal(t, []Partner{{UserName: "booyang", Age: 18}}, partners)
+
+	// write with the first name
+	outFileName := filepath.Join(t.TempDir(), "partner.xlsx")
+	require.NoError(t, p.Write(outFileName, "", partners))
+	data, err := NewParser().Parse(outFileName)
+	require.NoError(t, err)
+	require.Equal(t, []string{"user_name", "age"}, data.SheetNameData["Partners"].FieldKeys)
+}
+
+func Test_ReadColumnAliasRepeat(t *testing.T) {
+	var partners []Partner
+	err := NewParser().Read(newSheetFile(t, [][]string{
+		{"user_name", "age", "用户名"},
+		{"booyang", "18", "boo"},
+	}), &partners)
+	require.Error(t, err)
+	require.Equal(t, ErrorCodeColumnRepeat, ErrorList(err)[0].Code)
+	require.Equal(t, "C1", ErrorList(err)[0].Coordinates)
+}
+
+type Deal struct {
+	Buyer  string `excel:"column:buyer|name;optional"`
+	Seller string `excel:"column:seller|name;optional"`
+	Name   string `excel:"column:company|Buyer Name;optional"`
+}
+
+func Test_ReadColumnAliasConflict(t *testing.T) {
+	fileName := newSheetFile(t, [][]string{
+		{"name", "buyer_name"},
+		{"booyang", "boo"},
+	})
+	p := NewParser()
+	p.NormalizeHeader = true
+	// the first declared field wins the shared alias, the column name wins the alias of other fields
+	for i := 0; i < 20; i++ {
+		var deals []Deal
+		require.NoError(t, p.Read(fileName, &deals))
+		require.Equal(t, []Deal{{Buyer: "booyang", Name: "boo"}}, deals)
+	}
+}
+
+type Transfer struct {
+	Account string `excel:"column:account;col:A"`
+	Amount  int    `excel:"column:amount;index:3"`
+	Remark  string `excel:"column:B"`
+}
+
+func Test_ReadColumnPosition(t *testing.T) {
+	// the header text of the bound columns changes every month
+	var transfers []Transfer
+	err := NewParser().Read(newSheetFile(t, [][]string{
+		{"账号(10月)", "B", "金额(10月)"},
+		{"6222", "salary", "100"},
+	}), &transfers)
+	require.NoError(t, err)
+	require.Equal(t, []Transfer{{Account: "6222", Amount: 100, Remark: "salary"}}, transfers)
+
+	// sheet without header
+	fileName := newSheetFile(t, [][]string{
+		{"6222", "salary", "100"},
+		{"6223", "", "200", "extra"},
+	})
+	p := NewParser()
+	p.NoHeader = true
+	data, err := p.Parse(fileName)
+	require.NoError(t, err)
+	sheetData := data.SheetNameData["Sheet1"]
+	require.Equal(t, []string{"A", "B", "C", "D"}, sheetData.FieldKeys)
+	require.Equal(t, []int{1, 2}, sheetData.RowIndexes)
+	cell, err := sheetData.GetCell(2, "D")
+	require.NoError(t, err)
+	require.Equal(t, "extra", cell.Value)
+
+	err = p.Read(fileName, &transfers)
+	require.NoError(t, err)
+	require.Equal(t, []Transfer{
+		{Account: "6222", Amount: 100, Remark: "salary"},
+		{Account: "6223", Amount: 200},
+	}, transfers)
+
+	p.SheetSettings = map[string]SheetSetting{"Sheet1": {DataIndexOffset: 2}}
+	err = p.Read(fileName, &transfers)
+	require.NoError(t, err)
+	require.Equal(t, []Transfer{{Account: "6223", Amount: 200}}, transfers)
+}
+
+type BadTransfer struct {
+	Account string `excel:"column:account;col:C1"`
+	Amount  int    `excel:"column:amount;index:x"`
+}
+
+func Test_ReadColumnPositionInvalid(t *testing.T) {
+	fileName := newSheetFile(t, [][]string{
+		{"account", "amount"},
+		{"6222", "100"},
+	})
+	var transfers []BadTransfer
+	err := NewParser().Read(fileName, &transfers)
+	require.Error(t, err)
+	errs := ErrorList(err)
+	require.Len(t, errs, 2)
+	require.Equal(t, ErrorCodeTagInvalid, errs[0].Code)
+	require.Equal(t, "Account", errs[0].Field)
+	require.Equal(t, "col:C1", errs[0].Param)
+	require.Equal(t, "field Account: tag col:C1 is invalid", NewParser().ErrorMessage(errs[0]))
+	require.Equal(t, "Amount", errs[1].Field)
+	require.Equal(t, "index:x", errs[1].Param)
+
+	err = NewParser().Write(filepath.Join(t.TempDir(), "transfer.xlsx"), "", transfers)
+	require.ErrorIs(t, err, ErrorTagInvalid)
+}
+
+type Voucher struct {
+	Code  string `excel:"column:code;col:C"`
+	Name  string `excel:"column:name"`
+	Age   int    `excel:"column:age"`
+	Bonus int    `excel:"column:bonus;index:5"`
+}
+
+func Test_WriteReadColumnPosition(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "voucher.xlsx")
+	vouchers := []Voucher{{Code: "V001", Name: "booyang", Age: 18, Bonus: 10}, {Code: "V002", Name: "boo", Age: 20}}
+	p := NewParser()
+	require.NoError(t, p.Write(fileName, "", vouchers))
+
+	f, err := excelize.OpenFile(fileName)
+	require.NoError(t, err)
+	rows, err := f.GetRows("Vouchers")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.Equal(t, [][]string{
+		{"name", "age", "code", "", "bonus"},
+		{"booyang", "18", "V001", "", "10"},
+		{"boo", "20", "V002", "", "0"},
+	}, rows)
+
+	var newVouchers []Voucher
+	require.NoError(t, p.Read(fileName, &newVouchers))
+	require.Equal(t, vouchers, newVouchers)
+
+	// stream writer places the bound columns in the same way
+	w, err := p.NewStreamWriter(fileName, "", &Voucher{})
+	require.NoError(t, err)
+	for _, voucher := range vouchers {
+		require.NoError(t, w.Add(voucher))
+	}
+	require.NoError(t, w.Close())
+	newVouchers = nil
+	require.NoError(t, p.Read(fileName, &newVouchers))
+	require.Equal(t, vouchers, newVouchers)
+}
@@ -0,0 +1,163 @@
+package excelstructure
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"gopkg.in/yaml.v3"
+)
+
+// ColumnMapping 运行时字段到列名的映射，覆盖结构体标签中的column，用于同一结构体导入不同表头的文件
+// 返回的列名与column标签的格式相同，可以使用|分隔别名，-为跳过该字段，返回false则使用标签的设置
+// structType为读写的结构体，fieldName为字段路径，内联结构体的字段以.连接，如Body.Remark
+type ColumnMapping interface {
+	Column(structType reflect.Type, fieldName string) (string, bool)
+}
+
+// ColumnMap 字段到列名的映射，key为字段路径，如UserName、Body.Remark，
+// 或者以:限定结构体名的"结构体名:字段路径"，如Order:No、Athlete:Body.Remark，只对该结构体生效并优先
+//
+//	ColumnMap{"UserName": "客户名称|Name", "Order:No": "订单号"}
+type ColumnMap map[string]string
+
+// columnMapTypeSep the separator of the struct name and the field path of the ColumnMap key
+const columnMapTypeSep = ":"
+
+// Column ColumnMapping
+func (m ColumnMap) Column(structType reflect.Type, fieldName string) (string, bool) {
+	if column, ok := m[structType.Name()+columnMapTypeSep+fieldName]; ok {
+		return column, true
+	}
+	column, ok := m[fieldName]
+	return column, ok
+}
+
+// ColumnMappingFunc 函数形式的ColumnMapping
+type ColumnMappingFunc func(structType reflect.Type, fieldName string) (string, bool)
+
+// Column ColumnMapping
+func (f ColumnMappingFunc) Column(structType reflect.Type, fieldName string) (string, bool) {
+	return f(structType, fieldName)
+}
+
+// LoadColumnMapping 从json或者yaml文件加载ColumnMap，按扩展名.json、.yaml、.yml区分格式
+// 文件内容为字段路径到列名的映射，key的格式与ColumnMap相同，如 {"UserName": "客户名称", "Order:No": "订单号"}
+func LoadColumnMapping(fileName string) (ColumnMap, error) {
+	var unmarshal func(content []byte, v interface{}) error
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".json":
+		unmarshal = json.Unmarshal
+	case ".yaml", ".yml":
+		unmarshal = yaml.Unmarshal
+	default:
+		return nil, NewError(fileName, "", "", ErrorMappingFileType)
+	}
+
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, NewError(fileName, "", "", err)
+	}
+	mapping := make(ColumnMap)
+	if err = unmarshal(content, &mapping); err != nil {
+		return nil, NewError(fileName, "", "", err)
+	}
+	return mapping, nil
+}
+
+// WithColumnMapping 返回使用mapping的Parser副本，原Parser不受影响，用于单次读写
+// 副本复制注册的序列化器、转换器、语言和SheetSettings，在副本上注册不影响原Parser
+//
+//	err := p.WithColumnMapping(ColumnMap{"UserName": "客户名称"}).Read(fileName, &users)
+func (p *Parser) WithColumnMapping(mapping ColumnMapping) *Parser {
+	parser := *p
+	parser.ColumnMapping = mapping
+	parser.SheetSettings = make(map[string]SheetSetting, len(p.SheetSettings))
+	for sheetName, setting := range p.SheetSettings {
+		parser.SheetSettings[sheetName] = setting
+	}
+	parser.serializers = make(map[string]Serializer, len(p.serializers))
+	for name, serializer := range p.serializers {
+		parser.serializers[name] = serializer
+	}
+	parser.converters = make(map[reflect.Type]typeConverter, len(p.converters))
+	for t, converter := range p.converters {
+		parser.converters[t] = converter
+	}
+	parser.locales = make(map[string]MessageCatalog, len(p.locales))
+	for locale, catalog := range p.locales {
+		parser.locales[locale] = catalog
+	}
+	// 读写过程中的状态和缓存不共享
+	parser.excelFile, parser.styles, parser.sheets, parser.schemas = nil, nil, nil, nil
+	return &parser
+}
+
+// fieldTagSetting the tag settings of the struct fields with the column mapping applied,
+// the errors of invalid tag settings are returned with the file and sheet name of the parser
+func (p *Parser) fieldTagSetting(structType reflect.Type) (map[string]TagSetting, error) {
+	tagMap, err := parseFieldTagSetting(structType)
+	if p.ColumnMapping != nil {
+		err = p.applyColumnMapping(structType, tagMap, err)
+	}
+	WalkErrors(err, func(e *Error) bool {
+		e.FileName, e.SheetName = p.fileName, p.currentSheetName
+		return true
+	})
+	return tagMap, err
+}
+
+// applyColumnMapping replace the columns of the tag settings with the column mapping, the fields in order of the path,
+// a repeat field mapped to the column without the number placeholder is invalid,
+// the column of meta:coordinates follows the mapping of the column
+func (p *Parser) applyColumnMapping(structType reflect.Type, tagMap map[string]TagSetting, err error) error {
+	fieldNames := make([]string, 0, len(tagMap))
+	for fieldName := range tagMap {
+		fieldNames = append(fieldNames, fieldName)
+	}
+	sort.Strings(fieldNames)
+
+	var errs *multierror.Error
+	if err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	// 映射前的列名到映射后的列名，meta:coordinates的列随映射改变
+	renamed := make(map[string]string)
+	for _, fieldName := range fieldNames {
+		tagSetting := tagMap[fieldName]
+		if tagSetting.Inline || tagSetting.Extra || tagSetting.Meta != "" || tagSetting.Children != "" {
+			continue
+		}
+		column, ok := p.ColumnMapping.Column(structType, fieldName)
+		if !ok || column == "" {
+			continue
+		}
+		original := tagSetting.Column
+		tagSetting.Column, tagSetting.Aliases = column, nil
+		if names := strings.Split(column, "|"); len(names) > 1 {
+			tagSetting.Column, tagSetting.Aliases = names[0], names[1:]
+		}
+		tagSetting.Skip = tagSetting.Skip || tagSetting.Column == "-"
+		if _, ok := renamed[original]; !ok && !tagSetting.Skip {
+			renamed[original] = tagSetting.Column
+		}
+		if tagSetting.Repeat && !tagSetting.Skip && !isRepeatColumn(tagSetting.Column) {
+			tagSetting.Repeat = false
+			errs = multierror.Append(errs, tagError(fieldName, "repeat"))
+		} else if tagSetting.Repeat {
+			tagSetting.repeatPattern = repeatPattern(tagSetting.Column)
+		}
+		tagMap[fieldName] = tagSetting
+	}
+	for fieldName, tagSetting := range tagMap {
+		if column, ok := renamed[tagSetting.MetaColumn]; ok && tagSetting.Meta == MetaCoordinates {
+			tagSetting.MetaColumn = column
+			tagMap[fieldName] = tagSetting
+		}
+	}
+	return errs.ErrorOrNil()
+}
@@ -0,0 +1,97 @@
+package excelstructure
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReadWithColumnMapping(t *testing.T) {
+	fileName := newSheetFile(t, [][]string{
+		{"客户名称", "客户年龄"},
+		{"booyang", "18"},
+	})
+
+	p := NewParser()
+	var partners []Partner
+	err := p.WithColumnMapping(ColumnMap{"UserName": "客户名称", "Partner:Age": "客户年龄"}).Read(fileName, &partners)
+	require.NoError(t, err)
+	require.Equal(t, []Partner{{UserName: "booyang", Age: 18}}, partners)
+	// the parser is not changed
+	require.Nil(t, p.ColumnMapping)
+	require.Error(t, p.Read(fileName, &partners))
+
+	// registering on the copy does not change the parser
+	parser := p.WithColumnMapping(ColumnMap{})
+	require.NoError(t, parser.RegisterSerializer("mySerializer", mySerializer))
+	require.NoError(t, RegisterTypeConverter(parser, func(value string) (OrderStatus, error) {
+		return OrderStatusPaid, nil
+	}, func(v OrderStatus) (string, error) {
+		return "paid", nil
+	}))
+	require.Empty(t, p.serializers)
+	require.Empty(t, p.converters)
+	require.NoError(t, p.RegisterSerializer("mySerializer", mySerializer))
+
+	p.ColumnMapping = ColumnMappingFunc(func(structType reflect.Type, fieldName string) (string, bool) {
+		if fieldName == "Age" {
+			return "-", true
+		}
+		return "name|客户名称", true
+	})
+	err = p.Read(fileName, &partners)
+	require.NoError(t, err)
+	require.Equal(t, []Partner{{UserName: "booyang"}}, partners)
+
+	outFileName := filepath.Join(t.TempDir(), "partner.xlsx")
+	require.NoError(t, p.Write(outFileName, "", partners))
+	data, err := NewParser().Parse(outFileName)
+	require.NoError(t, err)
+	require.Equal(t, []string{"name"}, data.SheetNameData["Partners"].FieldKeys)
+}
+
+type Lead struct {
+	Name  string `excel:"column:name"`
+	Owner string `excel:"column:owner;skip"`
+}
+
+func Test_ColumnMappingKeepSkip(t *testing.T) {
+	p := NewParser()
+	p.ColumnMapping = ColumnMap{"Name": "客户名称", "Owner": "负责人"}
+	tagMap, err := p.fieldTagSetting(reflect.TypeOf(Lead{}))
+	require.NoError(t, err)
+	require.Equal(t, "客户名称", tagMap["Name"].Column)
+	// the skip tag is not overwritten by the mapping
+	require.True(t, tagMap["Owner"].Skip)
+}
+
+func Test_ColumnMapFieldPath(t *testing.T) {
+	p := NewParser()
+	// the field path of inline struct and the struct qualified key are not ambiguous
+	p.ColumnMapping = ColumnMap{"Body.Remark": "备注", "Athlete:Body.Size.Height": "身高", "Body.Size.Height": "height"}
+	tagMap, err := p.fieldTagSetting(reflect.TypeOf(Athlete{}))
+	require.NoError(t, err)
+	require.Equal(t, "备注", tagMap["Body.Remark"].Column)
+	require.Equal(t, "身高", tagMap["Body.Size.Height"].Column)
+	require.Equal(t, "body.size.weight", tagMap["Body.Size.Weight"].Column)
+}
+
+func Test_LoadColumnMapping(t *testing.T) {
+	dir := t.TempDir()
+	jsonFile := filepath.Join(dir, "partner.json")
+	require.NoError(t, os.WriteFile(jsonFile, []byte(`{"UserName": "客户名称", "Age": "客户年龄"}`), 0o600))
+	yamlFile := filepath.Join(dir, "partner.yaml")
+	require.NoError(t, os.WriteFile(yamlFile, []byte("UserName: 客户名称\nAge: 客户年龄\n"), 0o600))
+
+	for _, fileName := range []string{jsonFile, yamlFile} {
+		mapping, err := LoadColumnMapping(fileName)
+		require.NoError(t, err)
+		require.Equal(t, ColumnMap{"UserName": "客户名称", "Age": "客户年龄"}, mapping)
+	}
+
+	_, err := LoadColumnMapping(filepath.Join(dir, "partner.txt"))
+	require.ErrorIs(t, err, ErrorMappingFileType)
+}
@@ -0,0 +1,92 @@
+package excelstructure
+
+import (
+	"reflect"
+	"strings"
+)
+
+// typeConverter the converter of a field type registered by RegisterTypeConverter
+type typeConverter struct {
+	parse  func(value string) (interface{}, error)
+	format func(v interface{}) (string, error)
+}
+
+// RegisterTypeConverter 按类型注册转换器，类型为T或*T的字段读写时自动使用，无需serializer标签
+// 字段指定了serializer标签时优先使用serializer，包括结构体、切片和map等类型的serializer:json，
+// 基础类型(数字、字符串、布尔)的serializer:json与之前一样被忽略，仍然使用转换器
+//
+//	err := RegisterTypeConverter(p, decimal.NewFromString, func(v decimal.Decimal) (string, error) {
+//		return v.String(), nil
+//	})
+func RegisterTypeConverter[T any](p *Parser, parse func(value string) (T, error), format func(v T) (string, error)) error {
+	if parse == nil || format == nil {
+		return ErrorConverterHandlerEmpty
+	}
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if p.converters == nil {
+		p.converters = make(map[reflect.Type]typeConverter)
+	}
+	if _, ok := p.converters[t]; ok {
+		return ErrorConverterTypeRepeat
+	}
+
+	p.converters[t] = typeConverter{
+		parse: func(value string) (interface{}, error) {
+			return parse(value)
+		},
+		format: func(v interface{}) (string, error) {
+			return format(v.(T))
+		},
+	}
+	return nil
+}
+
+// fieldSetConverter set T or *T field by the type converter
+func (p *Parser) fieldSetConverter(field reflect.Value, cell *Cell, defaultValue string, converter typeConverter) error {
+	if !field.CanSet() {
+		return NewError(p.fileName, p.currentSheetName, cell.Coordinates, ErrorFieldNotSet)
+	}
+
+	value := cell.Value
+	if strings.TrimSpace(value) == "" {
+		value = defaultValue
+	}
+	if value == "" {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	v, err := converter.parse(value)
+	if err != nil {
+		return NewError(p.fileName, p.currentSheetName, cell.Coordinates, err)
+	}
+
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	if field.Kind() == reflect.Ptr {
+		ptr := reflect.New(field.Type().Elem())
+		ptr.Elem().Set(rv)
+		rv = ptr
+	}
+	field.Set(rv)
+	return nil
+}
+
+// converterCellValue the excel cell value of T or *T field by the type converter
+func converterCellValue(field reflect.Value, tagSetting TagSetting, converter typeConverter) (interface{}, error) {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return nil, nil
+		}
+		field = field.Elem()
+	}
+	if field.IsZero() && tagSetting.Default != "" {
+		return tagSetting.Default, nil
+	}
+
+	return converter.format(field.Interface())
+}
@@ -0,0 +1,100 @@
+package excelstructure
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+type Money struct {
+	Cents int64
+}
+
+func parseMoney(value string) (Money, error) {
+	f, err := strconv.ParseFloat(strings.TrimPrefix(value, "$"), 64)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Cents: int64(f*100 + 0.5)}, nil
+}
+
+func formatMoney(v Money) (string, error) {
+	return fmt.Sprintf("$%d.%02d", v.Cents/100, v.Cents%100), nil
+}
+
+type Invoice struct {
+	No       string `excel:"column:no"`
+	Amount   Money  `excel:"column:amount"`
+	Discount *Money `excel:"column:discount"`
+	Tax      Money  `excel:"column:tax;serializer:json"`
+}
+
+func Test_TypeConverter(t *testing.T) {
+	p := NewParser()
+	err := RegisterTypeConverter(p, parseMoney, formatMoney)
+	require.NoError(t, err)
+	err = RegisterTypeConverter(p, parseMoney, formatMoney)
+	require.Equal(t, ErrorConverterTypeRepeat, err)
+
+	fileName := filepath.Join(t.TempDir(), "invoice.xlsx")
+	discount := Money{Cents: 50}
+	invoices := []Invoice{
+		{No: "A001", Amount: Money{Cents: 1234}, Discount: &discount, Tax: Money{Cents: 100}},
+		{No: "A002", Amount: Money{Cents: 99}},
+	}
+	err = p.Write(fileName, "invoices", invoices)
+	require.NoError(t, err)
+
+	f, err := excelize.OpenFile(fileName)
+	require.NoError(t, err)
+	rows, err := f.GetRows("invoices")
+	require.NoError(t, err)
+	// explicit serializer tag takes priority over the converter
+	require.Equal(t, []string{"A001", "$12.34", "$0.50", `{"Cents":100}`}, rows[1])
+	require.Equal(t, []string{"A002", "$0.99", "", `{"Cents":0}`}, rows[2])
+	require.NoError(t, f.Close())
+
+	var newInvoices []Invoice
+	err = p.Read(fileName, &newInvoices)
+	require.NoError(t, err)
+	require.Equal(t, invoices, newInvoices)
+}
+
+type Percent int
+
+type Discount struct {
+	Rate   Percent `excel:"column:rate;serializer:json"`
+	Amount Money   `excel:"column:amount;serializer:json"`
+}
+
+func Test_TypeConverterJSONSerializer(t *testing.T) {
+	p := NewParser()
+	require.NoError(t, RegisterTypeConverter(p, parseMoney, formatMoney))
+	require.NoError(t, RegisterTypeConverter(p, func(value string) (Percent, error) {
+		v, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+		return Percent(v), err
+	}, func(v Percent) (string, error) {
+		return fmt.Sprintf("%d%%", v), nil
+	}))
+
+	fileName := filepath.Join(t.TempDir(), "discount.xlsx")
+	discounts := []Discount{{Rate: 15, Amount: Money{Cents: 250}}}
+	require.NoError(t, p.Write(fileName, "discounts", discounts))
+
+	f, err := excelize.OpenFile(fileName)
+	require.NoError(t, err)
+	rows, err := f.GetRows("discounts")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	// serializer:json of basic kind is ignored, of struct takes priority over the converter
+	require.Equal(t, []string{"15%", `{"Cents":250}`}, rows[1])
+
+	var newDiscounts []Discount
+	require.NoError(t, p.Read(fileName, &newDiscounts))
+	require.Equal(t, discounts, newDiscounts)
+}
@@ -3,13 +3,17 @@ package excelstructure
 import (
 	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/xuri/excelize/v2"
 )
 
 var (
 	// ErrorDataRowOutOfRange data row out of range
 	ErrorDataRowOutOfRange = errors.New("out of range")
 	// ErrorRowIndexIsHeader row index is header
-	ErrorRowIndexIsHeader = errors.New("row index must > 1, because row 1 is header")
+	ErrorRowIndexIsHeader = errors.New("row index is the header row")
 	// ErrorSheetName sheet name invalid
 	ErrorSheetName = errors.New("sheet name not exist")
 	// ErrorInOutputType output type invalid
@@ -25,8 +29,12 @@ var (
 	ErrorFieldNotExist = errors.New("field not exist in header")
 	// ErrorFieldRepeat repeat field
 	ErrorFieldRepeat = errors.New("field repeat")
+	// ErrorColumnUnknown header column not defined in struct
+	ErrorColumnUnknown = errors.New("column not defined in struct")
 	// ErrorFieldTypeNotSupport field type not support
 	ErrorFieldTypeNotSupport = errors.New("field type not support")
+	// ErrorTagInvalid struct tag invalid
+	ErrorTagInvalid = errors.New("struct tag invalid")
 	// ErrorFieldNotMatch Field type not match
 	ErrorFieldNotMatch = errors.New("field tag default value or excel value not match struct field type")
 	// ErrorFieldNotSet pointer field can not set
@@ -35,8 +43,24 @@ var (
 	ErrorFieldInvalid = errors.New("pointer or field invalid")
 	// ErrorFieldValueEmpty  field empty
 	ErrorFieldValueEmpty = errors.New("value is empty")
+	// ErrorValidateMin value less than min
+	ErrorValidateMin = errors.New("value less than min")
+	// ErrorValidateMax value greater than max
+	ErrorValidateMax = errors.New("value greater than max")
+	// ErrorValidateLen value length not match
+	ErrorValidateLen = errors.New("value length must be")
+	// ErrorValidateRegex value not match regex
+	ErrorValidateRegex = errors.New("value not match regex")
+	// ErrorValidateOneOf value not in options
+	ErrorValidateOneOf = errors.New("value must be one of")
+	// ErrorValidateEmail value not email
+	ErrorValidateEmail = errors.New("value is not a valid email")
+	// ErrorValidateURL value not url
+	ErrorValidateURL = errors.New("value is not a valid url")
 	// ErrorNoData no data
 	ErrorNoData = errors.New("no data")
+	// ErrorRecordType record type not match
+	ErrorRecordType = errors.New("record type not match the stream writer type")
 
 	// ErrorSerializerNameRepeat serializer name repeat
 	ErrorSerializerNameRepeat = errors.New("serializer name repeat")
@@ -44,6 +68,17 @@ var (
 	ErrorSerializerHandlerEmpty = errors.New("serializer marshal or unmarshal handler empty")
 	// ErrorSerializerNotExist serializer not exist
 	ErrorSerializerNotExist = errors.New("serializer not exist")
+
+	// ErrorConverterTypeRepeat converter type repeat
+	ErrorConverterTypeRepeat = errors.New("converter type repeat")
+	// ErrorConverterHandlerEmpty converter handler empty
+	ErrorConverterHandlerEmpty = errors.New("converter parse or format handler empty")
+	// ErrorForeignKeyNotExist foreign key not exist in parent sheet
+	ErrorForeignKeyNotExist = errors.New("foreign key not exist in parent sheet")
+	// ErrorSheetNameRepeat sheet name repeat
+	ErrorSheetNameRepeat = errors.New("sheet name repeat")
+	// ErrorMappingFileType column mapping file type not support
+	ErrorMappingFileType = errors.New("column mapping file type not support, must be json or yaml")
 )
 
 // Error excel structure error
@@ -51,17 +86,49 @@ type Error struct {
 	FileName    string
 	SheetName   string
 	Coordinates string
-	Err         error
+	// RowIndex ColIndex 错误所在的excel行号和列号，从1开始，0为未知
+	RowIndex int
+	ColIndex int
+	// Field 错误所在的结构体字段名
+	Field string
+	// Column 错误所在的表头列名
+	Column string
+	// Value 单元格的值
+	Value string
+	// Param 校验规则的参数，如min:18的18
+	Param string
+	// Code 错误码，可用于映射http状态码或者界面提示
+	Code ErrorCode
+	Err  error
 }
 
 // NewError new error
+// coordinates 为单元格坐标如A1、$A$1时解析出行列号，其他形式仅用于错误信息
 func NewError(filName string, sheetName string, coordinates string, err error) error {
-	return &Error{
+	e := &Error{
 		FileName:    filName,
 		SheetName:   sheetName,
 		Err:         err,
 		Coordinates: coordinates,
+		Code:        GetErrorCode(err),
+	}
+	e.parseCoordinates()
+	return e
+}
+
+// newRowError new error of the row and the header column which are not a cell, such as the missing column.
+// rowIndex 0 and empty column are not set, the coordinates of the message is like "rowIndex 1, fieldKey name"
+func newRowError(fileName, sheetName string, rowIndex int, column string, err error) error {
+	var parts []string
+	if rowIndex > 0 {
+		parts = append(parts, fmt.Sprintf("rowIndex %d", rowIndex))
 	}
+	if column != "" {
+		parts = append(parts, fmt.Sprintf("fieldKey %s", column))
+	}
+	e := NewError(fileName, sheetName, strings.Join(parts, ", "), err).(*Error)
+	e.RowIndex, e.Column = rowIndex, column
+	return e
 }
 
 // Error error
@@ -69,3 +136,72 @@ func (e *Error) Error() string {
 	return fmt.Sprintf("fileName: %s, SheetName: %s, Coordinates: %s , ErrMsg: %s",
 		e.FileName, e.SheetName, e.Coordinates, e.Err.Error())
 }
+
+// Unwrap the wrapped error, errors.Is(err, ErrorFieldNotMatch) is supported
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// parseCoordinates parse the row index and col index from the cell coordinates
+func (e *Error) parseCoordinates() {
+	if col, row, err := excelize.CellNameToCoordinates(strings.ReplaceAll(e.Coordinates, "$", "")); err == nil {
+		e.RowIndex, e.ColIndex = row, col
+	}
+}
+
+// setErrorField set the struct field, header column and cell value of the errors,
+// the errors of multierror are set one by one
+func setErrorField(err error, field, column, value string) {
+	WalkErrors(err, func(e *Error) bool {
+		if e.Field == "" {
+			e.Field = field
+		}
+		if e.Column == "" {
+			e.Column = column
+		}
+		if e.Value == "" {
+			e.Value = value
+		}
+		return true
+	})
+}
+
+// WalkErrors 遍历err中的所有*Error，支持*Report、multierror和wrap的错误，fn返回false时停止遍历
+func WalkErrors(err error, fn func(e *Error) bool) {
+	walkErrors(err, fn)
+}
+
+func walkErrors(err error, fn func(e *Error) bool) bool {
+	switch v := err.(type) {
+	case nil:
+		return true
+	case *Error:
+		return fn(v)
+	case *Report:
+		for _, item := range v.Items {
+			if !walkErrors(item.Err, fn) {
+				return false
+			}
+		}
+		return true
+	case *multierror.Error:
+		for _, e := range v.Errors {
+			if !walkErrors(e, fn) {
+				return false
+			}
+		}
+		return true
+	default:
+		return walkErrors(errors.Unwrap(err), fn)
+	}
+}
+
+// ErrorList err中的所有*Error，支持*Report、multierror和wrap的错误
+func ErrorList(err error) []*Error {
+	var errs []*Error
+	WalkErrors(err, func(e *Error) bool {
+		errs = append(errs, e)
+		return true
+	})
+	return errs
+}
@@ -0,0 +1,68 @@
+package excelstructure
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewError(t *testing.T) {
+	err := NewError("a.xlsx", "Sheet1", "$B$3", ErrorFieldNotMatch)
+	require.True(t, errors.Is(err, ErrorFieldNotMatch))
+	var e *Error
+	require.True(t, errors.As(err, &e))
+	require.Equal(t, 3, e.RowIndex)
+	require.Equal(t, 2, e.ColIndex)
+	require.Equal(t, ErrorCodeTypeMismatch, e.Code)
+
+	// the row and column which are not a cell are set directly, the coordinates text is not parsed
+	err = newRowError("a.xlsx", "Sheet1", 4, "Unit, Price", ErrorFieldNotExist)
+	require.True(t, errors.As(err, &e))
+	require.Equal(t, 4, e.RowIndex)
+	require.Equal(t, 0, e.ColIndex)
+	require.Equal(t, "Unit, Price", e.Column)
+	require.Equal(t, "rowIndex 4, fieldKey Unit, Price", e.Coordinates)
+	require.Equal(t, ErrorCodeColumnMissing, e.Code)
+
+	err = NewError("a.xlsx", "Sheet1", "sheetName row 3", ErrorSheetName)
+	require.True(t, errors.As(err, &e))
+	require.Equal(t, 0, e.RowIndex)
+	require.Equal(t, "", e.Column)
+
+	// wrapped sentinel error
+	err = NewError("a.xlsx", "Sheet1", "A2", fmt.Errorf("%w 18", ErrorValidateMin))
+	require.True(t, errors.Is(err, ErrorValidateMin))
+	require.Equal(t, ErrorCodeMin, GetErrorCode(err))
+	require.Equal(t, ErrorCodeInvalid, GetErrorCode(errors.New("custom")))
+
+	var errs error
+	errs = multierror.Append(errs, errors.New("custom"), err)
+	require.True(t, errors.Is(errs, ErrorValidateMin))
+	require.Equal(t, 1, len(ErrorList(errs)))
+}
+
+func Test_ErrorListOfReport(t *testing.T) {
+	fileName := newMemberFile(t)
+	var members []Member
+	err := NewParser().Read(fileName, &members)
+	require.True(t, errors.Is(err, ErrorValidateRegex))
+	require.False(t, errors.Is(err, ErrorSheetName))
+
+	errs := ErrorList(err)
+	require.Equal(t, 12, len(errs))
+	require.Equal(t, "Phone", errs[2].Field)
+	require.Equal(t, "phone", errs[2].Column)
+	require.Equal(t, 3, errs[2].RowIndex)
+	require.Equal(t, 3, errs[2].ColIndex)
+	require.Equal(t, ErrorCodeLen, errs[2].Code)
+
+	var rows []int
+	WalkErrors(err, func(e *Error) bool {
+		rows = append(rows, e.RowIndex)
+		return e.RowIndex < 4
+	})
+	require.Equal(t, []int{3, 3, 3, 3, 3, 3, 3, 3, 4}, rows)
+}
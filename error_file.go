@@ -0,0 +1,349 @@
+package excelstructure
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	// ErrorColumnName 错误标注文件中汇总每行错误的列的表头
+	ErrorColumnName = "errors"
+	// errorCommentAuthor 错误批注的作者
+	errorCommentAuthor = "excelstructure"
+	// errorStyleNamePrefix 以定义名称记录高亮样式对应的单元格原样式，名称为前缀+高亮样式ID，值为原样式ID
+	errorStyleNamePrefix = "_excelstructure_error_style_"
+)
+
+// errorCellFill 错误单元格的高亮填充，errorFontColor 错误单元格的字体颜色，与单元格原有的样式合并
+var (
+	errorCellFill  = excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"FFC7CE"}}
+	errorFontColor = "9C0006"
+)
+
+// WriteErrorFile 将读取的错误标注到源文件的副本并保存到outFileName，用户在excel中修改后可以重新上传
+// 错误单元格在原有样式上高亮并添加批注，表头后增加errors列汇总每行的错误，再次标注时复用已有的errors列，
+// 并清除所有sheet上次标注的批注、高亮和errors列，恢复单元格原来的样式，已修正的单元格不再标注
+// err 为读取返回的*Report，也可以是*Error或者包含*Error的multierror
+func (p *Parser) WriteErrorFile(fileName, outFileName string, err error) error {
+	excelFile, err1 := excelize.OpenFile(fileName)
+	if err1 != nil {
+		return NewError(fileName, "", "", err1)
+	}
+	defer func() {
+		_ = excelFile.Close()
+	}()
+
+	if err1 = p.annotateErrors(excelFile, fileName, err); err1 != nil {
+		return err1
+	}
+	if err1 = excelFile.SaveAs(outFileName); err1 != nil {
+		return NewError(outFileName, "", "", err1)
+	}
+	return nil
+}
+
+// WriteErrorFileToWriter 从reader读取源文件，标注错误后写入到w，fileName为逻辑文件名，仅用于错误信息
+func (p *Parser) WriteErrorFileToWriter(r io.Reader, w io.Writer, fileName string, err error) error {
+	excelFile, err1 := excelize.OpenReader(r)
+	if err1 != nil {
+		return NewError(fileName, "", "", err1)
+	}
+	defer func() {
+		_ = excelFile.Close()
+	}()
+
+	if err1 = p.annotateErrors(excelFile, fileName, err); err1 != nil {
+		return err1
+	}
+	if err1 = excelFile.Write(w); err1 != nil {
+		return NewError(fileName, "", "", err1)
+	}
+	return nil
+}
+
+// annotateErrors annotate the errors to the sheets of the excel file
+func (p *Parser) annotateErrors(excelFile *excelize.File, fileName string, err error) error {
+	sheetItems := make(map[string][]*ReportItem)
+	for _, item := range errorReportItems(err) {
+		if item.SheetName == "" {
+			continue
+		}
+		sheetItems[item.SheetName] = append(sheetItems[item.SheetName], item)
+	}
+
+	styles := readErrorStyles(excelFile)
+	layouts := readLayouts(excelFile)
+	// 没有错误的sheet也清除上次的标注，修正后重新上传的文件不再有标注
+	for _, sheetName := range excelFile.GetSheetList() {
+		setting := p.sheetSetting(sheetName, layouts)
+		headerRowIndex := setting.HeaderRowIndex
+		if setting.NoHeader {
+			headerRowIndex = 0
+		}
+		if err1 := p.annotateSheet(excelFile, sheetName, headerRowIndex, styles, sheetItems[sheetName]); err1 != nil {
+			return NewError(fileName, sheetName, "", err1)
+		}
+	}
+	return nil
+}
+
+// annotateSheet highlight and comment the error cells, write the errors of each row to the errors column,
+// the annotations of the last time are cleared first, the errors column is left empty if the sheet has no error
+func (p *Parser) annotateSheet(
+	excelFile *excelize.File, sheetName string, headerRowIndex int, styles *errorStyles, items []*ReportItem,
+) error {
+	if err := clearErrorAnnotations(excelFile, sheetName, styles); err != nil {
+		return err
+	}
+	rows, err := excelFile.GetRows(sheetName)
+	if err != nil {
+		return err
+	}
+	var header []string
+	if headerRowIndex > 0 && len(rows) >= headerRowIndex {
+		header = rows[headerRowIndex-1]
+	}
+
+	// 复用已有的errors列并清除之前的错误
+	errorColIndex := 0
+	for i, v := range header {
+		if v == ErrorColumnName {
+			errorColIndex = i + 1
+		}
+	}
+	if errorColIndex == 0 && len(items) == 0 {
+		return nil
+	}
+	if errorColIndex == 0 {
+		for _, row := range rows {
+			if len(row) > errorColIndex {
+				errorColIndex = len(row)
+			}
+		}
+		errorColIndex++
+	} else {
+		for rowIndex := headerRowIndex + 1; rowIndex <= len(rows); rowIndex++ {
+			cell, _ := excelize.CoordinatesToCellName(errorColIndex, rowIndex)
+			if err = excelFile.SetCellValue(sheetName, cell, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	var cells []string
+	var rowIndexes []int
+	cellMessages := make(map[string][]string)
+	rowMessages := make(map[int][]string)
+	for _, item := range items {
+		if e, ok := item.Err.(*Error); ok && e.Column == "" && e.ColIndex > 0 && e.ColIndex <= len(header) {
+			e.Column = header[e.ColIndex-1]
+		}
+		message := p.ErrorMessage(item.Err)
+		rowIndex := item.RowIndex
+		if item.Coordinates != "" {
+			_, row, err := excelize.CellNameToCoordinates(item.Coordinates)
+			if err != nil {
+				return err
+			}
+			if _, ok := cellMessages[item.Coordinates]; !ok {
+				cells = append(cells, item.Coordinates)
+			}
+			cellMessages[item.Coordinates] = append(cellMessages[item.Coordinates], message)
+			rowIndex = row
+		}
+		// sheet级别的错误标注在表头行，没有表头的sheet不标注
+		if rowIndex < 1 {
+			if headerRowIndex < 1 {
+				continue
+			}
+			rowIndex = headerRowIndex
+		}
+		if _, ok := rowMessages[rowIndex]; !ok {
+			rowIndexes = append(rowIndexes, rowIndex)
+		}
+		rowMessages[rowIndex] = append(rowMessages[rowIndex], message)
+	}
+
+	for _, cell := range cells {
+		if err = styles.highlight(excelFile, sheetName, cell); err != nil {
+			return err
+		}
+		if err = setErrorComment(excelFile, sheetName, cell, cellMessages[cell]); err != nil {
+			return err
+		}
+	}
+
+	var headerCell string
+	if headerRowIndex > 0 && len(items) == 0 {
+		headerCell, err = excelize.CoordinatesToCellName(errorColIndex, headerRowIndex)
+		if err != nil {
+			return err
+		}
+		return excelFile.SetCellValue(sheetName, headerCell, nil)
+	}
+	if headerRowIndex > 0 {
+		headerCell, err = excelize.CoordinatesToCellName(errorColIndex, headerRowIndex)
+		if err != nil {
+			return err
+		}
+		if err = excelFile.SetCellValue(sheetName, headerCell, ErrorColumnName); err != nil {
+			return err
+		}
+	}
+	for _, rowIndex := range rowIndexes {
+		// 表头行的错误以批注的形式添加到errors列的表头
+		if rowIndex == headerRowIndex {
+			if err = setErrorComment(excelFile, sheetName, headerCell, rowMessages[rowIndex]); err != nil {
+				return err
+			}
+			continue
+		}
+		cell, err := excelize.CoordinatesToCellName(errorColIndex, rowIndex)
+		if err != nil {
+			return err
+		}
+		if err = excelFile.SetCellValue(sheetName, cell, strings.Join(rowMessages[rowIndex], "\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clearErrorAnnotations delete the error comments of the last annotation and restore the original style of
+// the highlighted cells, the cells fixed since the last annotation are not highlighted again
+func clearErrorAnnotations(excelFile *excelize.File, sheetName string, styles *errorStyles) error {
+	comments, err := excelFile.GetComments(sheetName)
+	if err != nil {
+		return err
+	}
+	for _, comment := range comments {
+		if comment.Author != errorCommentAuthor {
+			continue
+		}
+		if err = excelFile.DeleteComment(sheetName, comment.Cell); err != nil {
+			return err
+		}
+		// 用户修改过样式的单元格保留其样式
+		cellStyleID, err := excelFile.GetCellStyle(sheetName, comment.Cell)
+		if err != nil {
+			continue
+		}
+		original, ok := styles.originals[cellStyleID]
+		if !ok {
+			continue
+		}
+		if err = excelFile.SetCellStyle(sheetName, comment.Cell, comment.Cell, original); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errorStyles the highlight styles merged from the original styles of the cells
+type errorStyles struct {
+	// highlights original style id to highlight style id
+	highlights map[int]int
+	// originals highlight style id to original style id, recorded in the workbook defined names
+	originals map[int]int
+}
+
+// readErrorStyles read the highlight styles recorded by the last annotation
+func readErrorStyles(excelFile *excelize.File) *errorStyles {
+	styles := &errorStyles{highlights: make(map[int]int), originals: make(map[int]int)}
+	for _, dn := range excelFile.GetDefinedName() {
+		if !strings.HasPrefix(dn.Name, errorStyleNamePrefix) || dn.Scope != "Workbook" {
+			continue
+		}
+		highlight, err := strconv.Atoi(strings.TrimPrefix(dn.Name, errorStyleNamePrefix))
+		if err != nil {
+			continue
+		}
+		original, err := strconv.Atoi(strings.TrimPrefix(dn.RefersTo, "="))
+		if err != nil {
+			continue
+		}
+		styles.highlights[original] = highlight
+		styles.originals[highlight] = original
+	}
+	return styles
+}
+
+// highlight set the highlight style merged from the style of the cell, keeping the number format, border and font
+func (s *errorStyles) highlight(excelFile *excelize.File, sheetName, cell string) error {
+	original, err := excelFile.GetCellStyle(sheetName, cell)
+	if err != nil {
+		return err
+	}
+	// 已经高亮的单元格不再合并
+	if _, ok := s.originals[original]; ok {
+		return nil
+	}
+
+	highlight, ok := s.highlights[original]
+	if !ok {
+		style, err := excelFile.GetStyle(original)
+		if err != nil {
+			return err
+		}
+		style.Fill = errorCellFill
+		font := excelize.Font{}
+		if style.Font != nil {
+			font = *style.Font
+		}
+		font.Color = errorFontColor
+		style.Font = &font
+		if highlight, err = excelFile.NewStyle(style); err != nil {
+			return err
+		}
+		// 不同的原样式合并后可能是相同的样式，恢复为最先记录的原样式
+		if _, ok := s.originals[highlight]; ok {
+			return excelFile.SetCellStyle(sheetName, cell, cell, highlight)
+		}
+		if err = excelFile.SetDefinedName(&excelize.DefinedName{
+			Name:     errorStyleNamePrefix + strconv.Itoa(highlight),
+			RefersTo: strconv.Itoa(original),
+		}); err != nil {
+			return err
+		}
+		s.highlights[original] = highlight
+		s.originals[highlight] = original
+	}
+	return excelFile.SetCellStyle(sheetName, cell, cell, highlight)
+}
+
+// setErrorComment replace the comment of the cell with the error messages
+func setErrorComment(excelFile *excelize.File, sheetName, cell string, messages []string) error {
+	if err := excelFile.DeleteComment(sheetName, cell); err != nil {
+		return err
+	}
+	return excelFile.AddComment(sheetName, excelize.Comment{
+		Author:    errorCommentAuthor,
+		Cell:      cell,
+		Paragraph: []excelize.RichTextRun{{Text: strings.Join(messages, "\n")}},
+	})
+}
+
+// errorReportItems the report items of the error, sheetName of the item is resolved from *Error if empty
+func errorReportItems(err error) []*ReportItem {
+	if err == nil {
+		return nil
+	}
+
+	var report *Report
+	if errors.As(err, &report) {
+		return report.Items
+	}
+
+	report = newReport("")
+	report.add("", nil, 0, err)
+	for _, item := range report.Items {
+		if e, ok := item.Err.(*Error); ok && item.SheetName == "" {
+			item.SheetName = e.SheetName
+		}
+	}
+	return report.Items
+}
@@ -0,0 +1,147 @@
+package excelstructure
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func Test_WriteErrorFile(t *testing.T) {
+	fileName := newMemberFile(t)
+	p := NewParser()
+	var members []Member
+	err := p.Read(fileName, &members)
+	require.Error(t, err)
+
+	outFileName := filepath.Join(t.TempDir(), "member_errors.xlsx")
+	require.NoError(t, p.WriteErrorFile(fileName, outFileName, err))
+
+	f, err1 := excelize.OpenFile(outFileName)
+	require.NoError(t, err1)
+	rows, err1 := f.GetRows("Sheet1")
+	require.NoError(t, err1)
+	require.Equal(t, ErrorColumnName, rows[0][7])
+	require.Equal(t, 6, len(rows[1]))
+	require.Equal(t, "row 3 column name: value is required\n"+
+		"row 3 column age: value must not be less than 18\n"+
+		"row 3 column phone: value length must be 11\n"+
+		"row 3 column phone: value 2380000000 is invalid\n"+
+		"row 3 column gender: value must be one of male|female\n"+
+		"row 3 column email: boo is not a valid email\n"+
+		"row 3 column website: example.com is not a valid url\n"+
+		"row 3 column level: value must not be less than 1", rows[2][7])
+
+	comments, err1 := f.GetComments("Sheet1")
+	require.NoError(t, err1)
+	commentMap := make(map[string]string)
+	for _, comment := range comments {
+		for _, run := range comment.Paragraph {
+			commentMap[comment.Cell] += run.Text
+		}
+	}
+	// two errors of C3 are in one comment
+	require.Equal(t, 11, len(commentMap))
+	require.Equal(t, "row 3 column phone: value length must be 11\n"+
+		"row 3 column phone: value 2380000000 is invalid", commentMap["C3"])
+
+	styleID, err1 := f.GetCellStyle("Sheet1", "C3")
+	require.NoError(t, err1)
+	require.NotZero(t, styleID)
+	styleID, err1 = f.GetCellStyle("Sheet1", "C2")
+	require.NoError(t, err1)
+	require.Zero(t, styleID)
+	require.NoError(t, f.Close())
+
+	// the fixed file is read without the errors column, annotate again in chinese reuses the errors column
+	err = p.Read(outFileName, &members)
+	require.Error(t, err)
+	p.Locale = LocaleZH
+	require.NoError(t, p.WriteErrorFile(outFileName, outFileName, err))
+	f, err1 = excelize.OpenFile(outFileName)
+	require.NoError(t, err1)
+	rows, err1 = f.GetRows("Sheet1")
+	require.NoError(t, err1)
+	require.Equal(t, 8, len(rows[0]))
+	require.Equal(t, "第4行 name 列: 值不能小于2\n第4行 age 列: 值不能大于60\n"+
+		"第4行 phone 列: 值1380000000a格式不正确\n第4行 level 列: 值abc格式不正确", rows[3][7])
+
+	// fix the phone of row 3, the comment and highlight of the fixed cell are cleared when annotating again
+	require.NoError(t, f.SetCellValue("Sheet1", "C3", "13800000001"))
+	require.NoError(t, f.Save())
+	require.NoError(t, f.Close())
+	err = p.Read(outFileName, &members)
+	require.Error(t, err)
+	require.NoError(t, p.WriteErrorFile(outFileName, outFileName, err))
+	f, err1 = excelize.OpenFile(outFileName)
+	require.NoError(t, err1)
+	comments, err1 = f.GetComments("Sheet1")
+	require.NoError(t, err1)
+	cells := make([]string, 0, len(comments))
+	for _, comment := range comments {
+		cells = append(cells, comment.Cell)
+	}
+	require.NotContains(t, cells, "C3")
+	require.Contains(t, cells, "A3")
+	styleID, err1 = f.GetCellStyle("Sheet1", "C3")
+	require.NoError(t, err1)
+	require.Zero(t, styleID)
+	styleID, err1 = f.GetCellStyle("Sheet1", "A3")
+	require.NoError(t, err1)
+	require.NotZero(t, styleID)
+	require.NoError(t, f.Close())
+}
+
+func Test_WriteErrorFileKeepStyle(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "partner.xlsx")
+	f := excelize.NewFile()
+	require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"user_name", "age"}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"booyang", "abc"}))
+	numFmtStyle, err := f.NewStyle(&excelize.Style{NumFmt: 2})
+	require.NoError(t, err)
+	require.NoError(t, f.SetCellStyle("Sheet1", "B2", "B2", numFmtStyle))
+	require.NoError(t, f.SaveAs(fileName))
+	require.NoError(t, f.Close())
+
+	p := NewParser()
+	var partners []Partner
+	err = p.Read(fileName, &partners)
+	require.Error(t, err)
+	outFileName := filepath.Join(t.TempDir(), "partner_errors.xlsx")
+	require.NoError(t, p.WriteErrorFile(fileName, outFileName, err))
+
+	// the highlight is merged into the number format of the cell
+	f, err = excelize.OpenFile(outFileName)
+	require.NoError(t, err)
+	styleID, err := f.GetCellStyle("Sheet1", "B2")
+	require.NoError(t, err)
+	require.NotEqual(t, numFmtStyle, styleID)
+	style, err := f.GetStyle(styleID)
+	require.NoError(t, err)
+	require.Equal(t, 2, style.NumFmt)
+	require.Equal(t, errorCellFill.Color, style.Fill.Color)
+
+	// fix the cell and annotate again without error, the annotations are cleared and the style is restored
+	require.NoError(t, f.SetCellValue("Sheet1", "B2", "18"))
+	require.NoError(t, f.Save())
+	require.NoError(t, f.Close())
+	err = p.Read(outFileName, &partners)
+	require.NoError(t, err)
+	require.NoError(t, p.WriteErrorFile(outFileName, outFileName, err))
+
+	f, err = excelize.OpenFile(outFileName)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, f.Close())
+	}()
+	comments, err := f.GetComments("Sheet1")
+	require.NoError(t, err)
+	require.Empty(t, comments)
+	styleID, err = f.GetCellStyle("Sheet1", "B2")
+	require.NoError(t, err)
+	require.Equal(t, numFmtStyle, styleID)
+	rows, err := f.GetRows("Sheet1")
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"user_name", "age"}, {"booyang", "18"}}, rows)
+}
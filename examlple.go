@@ -88,8 +88,7 @@ func TestWriteRead() {
 			fmt.Println(err)
 		}
 	*/
-	// 因为结构体字段有comment标签，所以写入的时候，数据从第三行开始，所以读取的时候，数据偏移量为2
-	p.DataIndexOffset = 2
+	// 结构体字段有comment标签，写入的时候数据从第三行开始，写入时会在excel中记录表头和注释行，读取时自动跳过注释行
 	var newInfo []*Info
 
 	err = p.Read("./test_excel_file/test_write.xlsx", &newInfo)
@@ -120,8 +119,7 @@ func TestParse() {
 	if err != nil {
 		return
 	}
-	// 因为结构体字段有comment标签，所以写入的时候，数据从第三行开始，所以读取的时候，数据偏移量为2
-	p.DataIndexOffset = 2
+	// 结构体字段有comment标签，写入的时候数据从第三行开始，写入时会在excel中记录表头和注释行，读取时自动跳过注释行
 	excelData, err := p.Parse("./test_excel_file/test_write.xlsx")
 	if err != nil {
 		fmt.Println(err)
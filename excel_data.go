@@ -1,7 +1,6 @@
 package excelstructure
 
 import (
-	"fmt"
 	"strconv"
 
 	sliceutil "github.com/booyangcc/utils/sliceutil"
@@ -28,10 +27,17 @@ type SheetData struct {
 	SheetName string
 	FileName  string
 	// Rows  map[rowIndex]map[keyField]*Cell
-	Rows      map[int]map[string]*Cell
-	FieldKeys []string
-	// DataIndexOffset data index offset.
+	Rows map[int]map[string]*Cell
+	// RowIndexes data row indexes in sheet order, range it to visit Rows in order
+	RowIndexes []int
+	FieldKeys  []string
+	// HeaderRowIndex header row index, start with 1.
+	HeaderRowIndex int
+	// DataIndexOffset data index offset, rows before and at the offset are not data.
 	DataIndexOffset int
+
+	// keyRowIndexes the row index of each field key of the vertical sheet, nil if not vertical
+	keyRowIndexes []int
 }
 
 // Data excel data.
@@ -46,20 +52,21 @@ type Data struct {
 // GetCell get cell.
 func (s *SheetData) GetCell(rowIndex int, fieldKey string, isCheckEmpty ...bool) (*Cell, error) {
 	if s.DataTotal < rowIndex-s.DataIndexOffset {
-		return nil, NewError(s.FileName, s.SheetName, fmt.Sprintf("rowIndex %d", rowIndex), ErrorDataRowOutOfRange)
+		return nil, newRowError(s.FileName, s.SheetName, rowIndex, "", ErrorDataRowOutOfRange)
+	}
+	if rowIndex == s.HeaderRowIndex {
+		return nil, newRowError(s.FileName, s.SheetName, rowIndex, "", ErrorRowIndexIsHeader)
 	}
-	if rowIndex == 1 {
-		return nil, NewError(s.FileName, s.SheetName, fmt.Sprintf("rowIndex %d", rowIndex), ErrorRowIndexIsHeader)
+	row, ok := s.Rows[rowIndex]
+	if !ok {
+		return nil, newRowError(s.FileName, s.SheetName, rowIndex, "", ErrorDataRowOutOfRange)
 	}
-	row := s.Rows[rowIndex]
 	if !sliceutil.InSlice(fieldKey, s.FieldKeys) {
-		return nil, NewError(s.FileName, s.SheetName,
-			fmt.Sprintf("rowIndex %d, fieldKey %s", rowIndex, fieldKey), ErrorFieldNotExist)
+		return nil, newRowError(s.FileName, s.SheetName, rowIndex, fieldKey, ErrorFieldNotExist)
 	}
 	cell, ok := row[fieldKey]
 	if !ok {
-		return nil, NewError(s.FileName, s.SheetName,
-			fmt.Sprintf("rowIndex %d, fieldKey %s", rowIndex, fieldKey), ErrorFieldNotExist)
+		return nil, newRowError(s.FileName, s.SheetName, rowIndex, fieldKey, ErrorFieldNotExist)
 	}
 
 	isCheck := false
@@ -68,8 +75,7 @@ func (s *SheetData) GetCell(rowIndex int, fieldKey string, isCheckEmpty ...bool)
 	}
 
 	if isCheck && cell.IsEmpty {
-		return nil, NewError(s.FileName, s.SheetName,
-			fmt.Sprintf("rowIndex %d, fieldKey %s", rowIndex, fieldKey), ErrorFieldValueEmpty)
+		return nil, newRowError(s.FileName, s.SheetName, rowIndex, fieldKey, ErrorFieldValueEmpty)
 	}
 
 	return cell, nil
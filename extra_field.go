@@ -0,0 +1,152 @@
+package excelstructure
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// isExtraType the field type of extra tag must be map[string]string or map[string]interface{}
+func isExtraType(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String &&
+		(t.Elem().Kind() == reflect.String || t.Elem().Kind() == reflect.Interface)
+}
+
+// boundColumns the columns of fieldKeys bound to the struct fields, the extra field collects the other columns
+func boundColumns(fields []structField, tagMap map[string]TagSetting, fieldKeys []string) map[string]struct{} {
+	columns := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		tagSetting, ok := tagMap[field.Name]
+		if !ok {
+			tagSetting = TagSetting{Column: field.Name}
+		}
+		if tagSetting.Skip || tagSetting.Extra {
+			continue
+		}
+		if tagSetting.Repeat {
+			for _, column := range repeatColumns(tagSetting.repeatPattern, fieldKeys) {
+				columns[column.column] = struct{}{}
+			}
+			continue
+		}
+		columns[tagSetting.Column] = struct{}{}
+	}
+	return columns
+}
+
+// hasExtraField whether the struct has a field with extra tag
+func hasExtraField(tagMap map[string]TagSetting) bool {
+	for _, tagSetting := range tagMap {
+		if tagSetting.Extra {
+			return true
+		}
+	}
+	return false
+}
+
+// setExtraField set the non-empty cells of the columns not bound to other fields to the extra map field,
+// the nil struct pointers on the way are allocated only if the map is not empty
+func (p *Parser) setExtraField(
+	ve reflect.Value, field structField, sheetData *SheetData, rowIndex int, columns map[string]struct{},
+) {
+	extra := reflect.MakeMap(field.Type)
+	for _, fieldKey := range sheetData.FieldKeys {
+		if _, ok := columns[fieldKey]; ok || fieldKey == "" || fieldKey == ErrorColumnName {
+			continue
+		}
+		cell, ok := sheetData.Rows[rowIndex][fieldKey]
+		if !ok || cell.Value == "" {
+			continue
+		}
+		extra.SetMapIndex(reflect.ValueOf(fieldKey).Convert(field.Type.Key()),
+			reflect.ValueOf(cell.Value).Convert(field.Type.Elem()))
+	}
+	if extra.Len() == 0 {
+		return
+	}
+
+	fieldValue, _ := fieldByIndex(ve, field.Index, true)
+	fieldValue.Set(extra)
+}
+
+// extraColumns the union of the extra map keys of all records in sorted order, written as the extra header columns
+func extraColumns(rv reflect.Value, tagMap map[string]TagSetting) []string {
+	if !hasExtraField(tagMap) || rv.Len() == 0 {
+		return nil
+	}
+
+	var fields []structField
+	keys := make(map[string]struct{})
+	for i := 0; i < rv.Len(); i++ {
+		elemValue := reflect.Indirect(rv.Index(i))
+		if !elemValue.IsValid() {
+			continue
+		}
+		if fields == nil {
+			fields = structFields(elemValue.Type(), tagMap)
+		}
+		for _, field := range fields {
+			if !tagMap[field.Name].Extra {
+				continue
+			}
+			fieldValue, ok := fieldByIndex(elemValue, field.Index, false)
+			if !ok {
+				continue
+			}
+			for _, key := range fieldValue.MapKeys() {
+				keys[key.String()] = struct{}{}
+			}
+		}
+	}
+
+	columns := make([]string, 0, len(keys))
+	for key := range keys {
+		columns = append(columns, key)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// checkExtraColumns the extra map keys must not be the columns bound to the other fields,
+// otherwise the column is written twice and the file can not be read back
+func (p *Parser) checkExtraColumns(structType reflect.Type, tagMap map[string]TagSetting, dynamic dynamicColumns) error {
+	if len(dynamic.extra) == 0 {
+		return nil
+	}
+
+	fields := structFields(structType, tagMap)
+	var repeatColumns []string
+	for _, field := range fields {
+		tagSetting := tagMap[field.Name]
+		if !tagSetting.Repeat {
+			continue
+		}
+		for n := 1; n <= dynamic.repeats[field.Name]; n++ {
+			repeatColumns = append(repeatColumns, repeatColumnName(tagSetting.Column, n))
+		}
+	}
+	columns := boundColumns(fields, tagMap, repeatColumns)
+
+	var errs error
+	for _, column := range dynamic.extra {
+		if _, ok := columns[column]; ok {
+			errs = multierror.Append(errs, newRowError(p.fileName, p.currentSheetName, 0, column, ErrorFieldRepeat))
+		}
+	}
+	return errs
+}
+
+// extraCellValues the cell values of the extra map in the order of extra columns, nil if the key is absent
+func extraCellValues(fieldValue reflect.Value, columns []string) []interface{} {
+	values := make([]interface{}, len(columns))
+	if fieldValue.IsNil() {
+		return values
+	}
+	for i, column := range columns {
+		if v := fieldValue.MapIndex(reflect.ValueOf(column).Convert(fieldValue.Type().Key())); v.IsValid() {
+			values[i] = v.Interface()
+		}
+	}
+	return values
+}
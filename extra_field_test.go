@@ -0,0 +1,66 @@
+package excelstructure
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type Product struct {
+	Name  string            `excel:"column:name"`
+	Attrs map[string]string `excel:"extra"`
+	Price int               `excel:"column:price"`
+}
+
+type Device struct {
+	Name  string                 `excel:"column:name"`
+	Attrs map[string]interface{} `excel:"extra"`
+}
+
+func Test_WriteReadExtraField(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "product.xlsx")
+	products := []Product{
+		{Name: "phone", Attrs: map[string]string{"color": "black", "size": "6.1"}, Price: 100},
+		{Name: "pad", Attrs: map[string]string{"weight": "500g", "color": "white"}, Price: 200},
+		{Name: "pen", Price: 1},
+	}
+	p := NewParser()
+	p.StrictHeader = true
+	require.NoError(t, p.Write(fileName, "", products))
+
+	data, err := NewParser().Parse(fileName)
+	require.NoError(t, err)
+	sheetData := data.SheetNameData["Products"]
+	require.Equal(t, []string{"name", "color", "size", "weight", "price"}, sheetData.FieldKeys)
+
+	var newProducts []Product
+	require.NoError(t, p.Read(fileName, &newProducts))
+	require.Equal(t, products, newProducts)
+
+	var devices []Device
+	require.NoError(t, p.Read(fileName, &devices))
+	require.Equal(t, []Device{
+		{Name: "phone", Attrs: map[string]interface{}{"color": "black", "size": "6.1", "price": "100"}},
+		{Name: "pad", Attrs: map[string]interface{}{"weight": "500g", "color": "white", "price": "200"}},
+		{Name: "pen", Attrs: map[string]interface{}{"price": "1"}},
+	}, devices)
+
+	// the extra key bound to the other field is not written twice
+	products[0].Attrs["price"] = "99"
+	err = p.Write(fileName, "", products)
+	require.ErrorIs(t, err, ErrorFieldRepeat)
+	require.Equal(t, "price", ErrorList(err)[0].Column)
+}
+
+type Gadget struct {
+	Name  string         `excel:"column:name"`
+	Specs map[string]int `excel:"extra"`
+}
+
+func Test_ExtraFieldInvalidType(t *testing.T) {
+	var gadgets []Gadget
+	err := NewParser().Read(newSheetFile(t, [][]string{{"name"}, {"phone"}}), &gadgets)
+	require.ErrorIs(t, err, ErrorTagInvalid)
+	require.Equal(t, "Specs", ErrorList(err)[0].Field)
+}
@@ -0,0 +1,108 @@
+package excelstructure
+
+// Read 读取sheet到[]T，T必须是struct或者struct指针
+// sheetName 可选，为空则读取第一个sheet
+// 读取出错时仍会返回已成功解析的行
+func Read[T any](p *Parser, fileName string, sheetName ...string) ([]T, error) {
+	name := ""
+	if len(sheetName) > 0 {
+		name = sheetName[0]
+	}
+
+	var output []T
+	err := p.ReadWithSheetName(fileName, name, &output)
+	return output, err
+}
+
+// Write 写入records到单个sheet，T必须是struct或者struct指针
+// sheetName sheet名称，为空则为结构体元素的类型+s
+func Write[T any](p *Parser, fileName, sheetName string, records []T) error {
+	return p.WriteWithSheetName(fileName, sheetName, records)
+}
+
+// ReadEach 流式读取sheet，每解析一行调用一次fn，内存占用与sheet大小无关
+// fn返回错误则停止读取并返回该错误，行解析错误不会中断读取，最后以Report一并返回
+// Parser.FailFast为true时遇到第一个行解析错误即停止
+func ReadEach[T any](p *Parser, fileName, sheetName string, fn func(rowIndex int, record T) error) error {
+	it, err := p.OpenRows(fileName, sheetName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = it.Close()
+	}()
+
+	report := newReport(fileName)
+	for it.Next() {
+		var record T
+		if err = it.Scan(&record); err != nil {
+			report.add(it.sheetData.SheetName, it.FieldKeys(), it.RowIndex(), err)
+			if p.FailFast {
+				break
+			}
+			continue
+		}
+		if err = fn(it.RowIndex(), record); err != nil {
+			return err
+		}
+	}
+	if err = it.Err(); err != nil {
+		report.add(it.sheetData.SheetName, it.FieldKeys(), 0, err)
+	}
+
+	return report.errorOrNil()
+}
+
+// WriteChan 流式写入records到单个sheet，直到channel关闭，适用于大量数据导出
+// sheetName sheet名称，为空则为结构体元素的类型+s
+// 写入出错时丢弃channel中剩余的记录直到channel关闭后返回，生产者不会被阻塞
+func WriteChan[T any](p *Parser, fileName, sheetName string, records <-chan T) error {
+	var elem T
+	sw, err := p.NewStreamWriter(fileName, sheetName, elem)
+	if err != nil {
+		drain(records)
+		return err
+	}
+
+	for record := range records {
+		if err = sw.Add(record); err != nil {
+			_ = sw.excelFile.Close()
+			drain(records)
+			return err
+		}
+	}
+
+	return sw.Close()
+}
+
+// drain discard the records until the channel is closed
+func drain[T any](records <-chan T) {
+	for range records {
+	}
+}
+
+// Sheet typed sheet handle, bind a sheet name to the struct type T.
+type Sheet[T any] struct {
+	// Name sheet name, empty means the first sheet when reading
+	// and struct type name + s when writing
+	Name   string
+	parser *Parser
+}
+
+// NewSheet new typed sheet handle
+func NewSheet[T any](p *Parser, sheetName string) *Sheet[T] {
+	return &Sheet[T]{
+		Name:   sheetName,
+		parser: p,
+	}
+}
+
+// Read read the sheet to []T
+func (s *Sheet[T]) Read(fileName string) ([]T, error) {
+	return Read[T](s.parser, fileName, s.Name)
+}
+
+// Write write records to the sheet
+func (s *Sheet[T]) Write(fileName string, records []T) error {
+	return Write(s.parser, fileName, s.Name, records)
+}
@@ -0,0 +1,29 @@
+package excelstructure
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GenericWriteRead(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "generic.xlsx")
+	persons := []Person{
+		{Name: "booyang", Age: 18, Man: true, Address: []string{"beijing"}, Detail: Detail{Nation: "China"}},
+		{Name: "bob", Age: 17, Man: true, Address: []string{"london"}, Detail: Detail{Nation: "Britain"}},
+	}
+	p := NewParser()
+	err := Write(p, fileName, "persons", persons)
+	assert.NoError(t, err)
+
+	values, err := Read[Person](p, fileName, "persons")
+	assert.NoError(t, err)
+	require.Equal(t, persons, values)
+
+	pointers, err := NewSheet[*Person](p, "persons").Read(fileName)
+	assert.NoError(t, err)
+	require.Equal(t, 2, len(pointers))
+	require.Equal(t, "bob", pointers[1].Name)
+}
@@ -0,0 +1,90 @@
+package excelstructure
+
+import (
+	"reflect"
+	"regexp"
+
+	sliceutil "github.com/booyangcc/utils/sliceutil"
+)
+
+// checkHeader check the sheet header by the struct before decoding rows, report every missing column,
+// duplicate column and unknown column in strict mode at once, return true if the rows can not be decoded.
+// the order of columns does not matter, knownColumns are the columns not bound to fields but not unknown
+func (p *Parser) checkHeader(
+	sheetData *SheetData, structType reflect.Type, tagMap map[string]TagSetting, report *Report,
+	knownColumns ...string,
+) bool {
+	sheetName, headerRowIndex := sheetData.SheetName, sheetData.HeaderRowIndex
+	invalid := false
+	addError := func(err error) bool {
+		invalid = true
+		report.add(sheetName, sheetData.FieldKeys, headerRowIndex, err)
+		return p.FailFast
+	}
+
+	fields := structFields(structType, tagMap)
+	columns := make(map[string]struct{}, len(fields))
+	var repeats []*regexp.Regexp
+	for _, column := range knownColumns {
+		columns[column] = struct{}{}
+	}
+	for _, field := range fields {
+		tagSetting, ok := tagMap[field.Name]
+		if !ok {
+			tagSetting = TagSetting{Column: field.Name}
+		}
+		if tagSetting.Skip || tagSetting.Extra {
+			continue
+		}
+		// 重复列的数量不固定，可以没有
+		if tagSetting.Repeat {
+			repeats = append(repeats, tagSetting.repeatPattern)
+			continue
+		}
+		columns[tagSetting.Column] = struct{}{}
+		if tagSetting.Optional || sliceutil.InSlice(tagSetting.Column, sheetData.FieldKeys) {
+			continue
+		}
+		if addError(newRowError(p.fileName, sheetName, headerRowIndex, tagSetting.Column, ErrorFieldNotExist)) {
+			return true
+		}
+	}
+
+	hasExtra := hasExtraField(tagMap)
+	seen := make(map[string]struct{}, len(sheetData.FieldKeys))
+	for i, fieldKey := range sheetData.FieldKeys {
+		// 空表头和错误标注文件的errors列不检查
+		if fieldKey == "" || fieldKey == ErrorColumnName {
+			continue
+		}
+		coordinates, err := sheetData.keyCoordinates(i, p.IsCoordinatesABS)
+		if err != nil {
+			continue
+		}
+
+		if _, ok := seen[fieldKey]; ok && !p.AllowFieldRepeat {
+			if addError(NewError(p.fileName, sheetName, coordinates, ErrorFieldRepeat)) {
+				return true
+			}
+		}
+		seen[fieldKey] = struct{}{}
+
+		// 有extra字段时其他列都由extra字段收集
+		if _, ok := columns[fieldKey]; !ok && p.StrictHeader && !hasExtra && !matchRepeatPatterns(repeats, fieldKey) {
+			if addError(NewError(p.fileName, sheetName, coordinates, ErrorColumnUnknown)) {
+				return true
+			}
+		}
+	}
+	return invalid
+}
+
+// matchRepeatPatterns whether the column matches any pattern of the repeat fields
+func matchRepeatPatterns(patterns []*regexp.Regexp, column string) bool {
+	for _, pattern := range patterns {
+		if _, ok := matchRepeatColumn(pattern, column); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package excelstructure
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type Contact struct {
+	Name   string `excel:"column:name"`
+	Age    int    `excel:"column:age"`
+	Phone  string `excel:"column:phone"`
+	Email  string `excel:"column:email"`
+	Remark string `excel:"column:remark;optional;default:none"`
+	Level  *int   `excel:"column:level;optional;required"`
+}
+
+func Test_CheckHeader(t *testing.T) {
+	fileName := newSheetFile(t, [][]string{
+		{"age", "name", "name", "extra", "", "errors"},
+		{"18", "booyang", "boo"},
+	})
+
+	p := NewParser()
+	var contacts []Contact
+	err := p.Read(fileName, &contacts)
+	var report *Report
+	require.True(t, errors.As(err, &report))
+	// every header error is reported at once, optional columns are not missing
+	require.Equal(t, 3, report.Len())
+	require.Equal(t, ErrorCodeColumnMissing, report.Items[0].Code)
+	require.Equal(t, "phone", report.Items[0].Column)
+	require.Equal(t, ErrorCodeColumnMissing, report.Items[1].Code)
+	require.Equal(t, "email", report.Items[1].Column)
+	require.Equal(t, ErrorCodeColumnRepeat, report.Items[2].Code)
+	require.Equal(t, "C1", report.Items[2].Coordinates)
+	require.Equal(t, "name", report.Items[2].Column)
+	require.Equal(t, 0, len(contacts))
+
+	p.StrictHeader = true
+	err = p.Read(fileName, &contacts)
+	require.True(t, errors.As(err, &report))
+	require.Equal(t, 4, report.Len())
+	require.Equal(t, ErrorCodeColumnUnknown, report.Items[3].Code)
+	require.Equal(t, "extra", report.Items[3].Column)
+	require.Equal(t, "表头extra列无法识别", (&Parser{Locale: LocaleZH}).ErrorMessage(report.Items[3].Err))
+
+	// duplicate columns are still checked by Parse without struct
+	_, err = NewParser().Parse(fileName)
+	require.True(t, errors.Is(err, ErrorFieldRepeat))
+}
+
+func Test_ReadOptionalColumn(t *testing.T) {
+	fileName := newSheetFile(t, [][]string{
+		{"email", "phone", "age", "name"},
+		{"boo@example.com", "13800000000", "18", "booyang"},
+	})
+
+	p := NewParser()
+	p.StrictHeader = true
+	var contacts []Contact
+	err := p.Read(fileName, &contacts)
+	require.NoError(t, err)
+	require.Equal(t, []Contact{{
+		Name: "booyang", Age: 18, Phone: "13800000000", Email: "boo@example.com", Remark: "none",
+	}}, contacts)
+}
@@ -0,0 +1,23 @@
+package excelstructure
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// newSheetFile create a file in the temp dir, rows are written to Sheet1 from A1
+func newSheetFile(t *testing.T, rows [][]string) string {
+	fileName := filepath.Join(t.TempDir(), "sheet.xlsx")
+	f := excelize.NewFile()
+	for i, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		require.NoError(t, err)
+		require.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	require.NoError(t, f.SaveAs(fileName))
+	require.NoError(t, f.Close())
+	return fileName
+}
@@ -0,0 +1,53 @@
+package excelstructure
+
+import (
+	"reflect"
+)
+
+// structField the field mapped to a column, the fields of inline structs are flattened
+type structField struct {
+	// Name the field path, such as Detail.Height, key of the tag map
+	Name string
+	// Index the index sequence of the field from the root struct
+	Index []int
+	Type  reflect.Type
+}
+
+// structFields the fields of structType mapped to columns in order, inline structs are expanded by the tag map
+func structFields(structType reflect.Type, tagMap map[string]TagSetting) []structField {
+	return appendStructFields(nil, structType, tagMap, "", nil)
+}
+
+func appendStructFields(
+	fields []structField, structType reflect.Type, tagMap map[string]TagSetting, path string, index []int,
+) []structField {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name := path + field.Name
+		fieldIndex := append(append(make([]int, 0, len(index)+1), index...), i)
+		if tagSetting, ok := tagMap[name]; ok && tagSetting.Inline {
+			fields = appendStructFields(fields, indirectType(field.Type), tagMap, name+".", fieldIndex)
+			continue
+		}
+		fields = append(fields, structField{Name: name, Index: fieldIndex, Type: field.Type})
+	}
+	return fields
+}
+
+// fieldByIndex the nested field of v by index, the nil struct pointers on the way are allocated if alloc is true,
+// return false if a nil pointer is met and not allocated
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
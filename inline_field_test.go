@@ -0,0 +1,79 @@
+package excelstructure
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type Audit struct {
+	Creator string `excel:"column:creator"`
+}
+
+type Size struct {
+	Height int `excel:"column:height"`
+	Weight int `excel:"column:weight"`
+}
+
+type Body struct {
+	Size   Size   `excel:"prefix:size."`
+	Remark string `excel:"column:remark"`
+}
+
+type Athlete struct {
+	Audit `excel:"inline"`
+	Name  string `excel:"column:name"`
+	Body  *Body  `excel:"prefix:body."`
+}
+
+func Test_WriteReadInlineField(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "athlete.xlsx")
+	athletes := []Athlete{
+		{
+			Audit: Audit{Creator: "admin"},
+			Name:  "booyang",
+			Body:  &Body{Size: Size{Height: 180, Weight: 70}, Remark: "fast"},
+		},
+		{Name: "boo"},
+	}
+	p := NewParser()
+	require.NoError(t, p.Write(fileName, "", athletes))
+
+	data, err := NewParser().Parse(fileName)
+	require.NoError(t, err)
+	sheetData := data.SheetNameData["Athletes"]
+	require.Equal(t, []string{"creator", "name", "body.size.height", "body.size.weight", "body.remark"},
+		sheetData.FieldKeys)
+	cell, err := sheetData.GetCell(2, "body.size.height")
+	require.NoError(t, err)
+	require.Equal(t, "180", cell.Value)
+
+	var newAthletes []Athlete
+	require.NoError(t, p.Read(fileName, &newAthletes))
+	// the nil pointer is kept nil when all the columns are empty
+	require.Equal(t, athletes, newAthletes)
+}
+
+func Test_ReadInlineFieldError(t *testing.T) {
+	var athletes []Athlete
+	err := NewParser().Read(newSheetFile(t, [][]string{
+		{"creator", "name", "body.size.height", "body.remark"},
+		{"admin", "booyang", "tall"},
+	}), &athletes)
+	require.Error(t, err)
+	errs := ErrorList(err)
+	require.Len(t, errs, 1)
+	require.Equal(t, ErrorCodeColumnMissing, errs[0].Code)
+	require.Equal(t, "body.size.weight", errs[0].Column)
+
+	err = NewParser().Read(newSheetFile(t, [][]string{
+		{"creator", "name", "body.size.height", "body.size.weight", "body.remark"},
+		{"admin", "booyang", "tall", "70"},
+	}), &athletes)
+	require.Error(t, err)
+	errs = ErrorList(err)
+	require.Len(t, errs, 1)
+	require.Equal(t, "Body.Size.Height", errs[0].Field)
+	require.Equal(t, "C2", errs[0].Coordinates)
+}
@@ -0,0 +1,94 @@
+package excelstructure
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// 写入时以sheet作用域的定义名称记录表头行、注释行和数据起始行，读取时自动识别
+// 定义名称引用整行，在excel中插入或删除行后引用会自动更新
+const (
+	// LayoutHeaderName defined name of the header row
+	LayoutHeaderName = "_excelstructure_header"
+	// LayoutCommentName defined name of the comment row
+	LayoutCommentName = "_excelstructure_comment"
+	// LayoutDataName defined name of the first data row
+	LayoutDataName = "_excelstructure_data"
+)
+
+// sheetLayout the layout of sheet written by parser, row index start with 1, 0 means not exist
+type sheetLayout struct {
+	HeaderRowIndex  int
+	CommentRowIndex int
+	DataRowIndex    int
+}
+
+// writeLayout record the sheet layout to the workbook defined names
+func writeLayout(ef *excelize.File, sheetName string, layout sheetLayout) error {
+	names := map[string]int{
+		LayoutHeaderName:  layout.HeaderRowIndex,
+		LayoutCommentName: layout.CommentRowIndex,
+		LayoutDataName:    layout.DataRowIndex,
+	}
+	for _, name := range []string{LayoutHeaderName, LayoutCommentName, LayoutDataName} {
+		rowIndex := names[name]
+		if rowIndex < 1 {
+			continue
+		}
+		err := ef.SetDefinedName(&excelize.DefinedName{
+			Name:     name,
+			RefersTo: fmt.Sprintf("'%s'!$%d:$%d", strings.ReplaceAll(sheetName, "'", "''"), rowIndex, rowIndex),
+			Scope:    sheetName,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readLayouts read the sheet layouts from the workbook defined names, key is sheetName
+func readLayouts(ef *excelize.File) map[string]sheetLayout {
+	layouts := make(map[string]sheetLayout)
+	for _, dn := range ef.GetDefinedName() {
+		if dn.Name != LayoutHeaderName && dn.Name != LayoutCommentName && dn.Name != LayoutDataName {
+			continue
+		}
+		rowIndex := parseRowReference(dn.RefersTo)
+		if rowIndex < 1 {
+			continue
+		}
+
+		layout := layouts[dn.Scope]
+		switch dn.Name {
+		case LayoutHeaderName:
+			layout.HeaderRowIndex = rowIndex
+		case LayoutCommentName:
+			layout.CommentRowIndex = rowIndex
+		case LayoutDataName:
+			layout.DataRowIndex = rowIndex
+		}
+		layouts[dn.Scope] = layout
+	}
+
+	for sheetName, layout := range layouts {
+		if layout.HeaderRowIndex < 1 || layout.DataRowIndex <= layout.HeaderRowIndex {
+			delete(layouts, sheetName)
+		}
+	}
+	return layouts
+}
+
+// parseRowReference parse the row index of reference like 'Sheet1'!$3:$3, return 0 if invalid
+func parseRowReference(refersTo string) int {
+	ref := refersTo[strings.LastIndex(refersTo, "!")+1:]
+	ref = strings.SplitN(ref, ":", 2)[0]
+	rowIndex, err := strconv.Atoi(strings.TrimPrefix(ref, "$"))
+	if err != nil {
+		return 0
+	}
+	return rowIndex
+}
@@ -0,0 +1,139 @@
+package excelstructure
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// LocaleEN english
+	LocaleEN = "en"
+	// LocaleZH 中文
+	LocaleZH = "zh"
+)
+
+// MessageCatalog 错误消息模板，key为错误码
+// 模板中可以使用的变量:
+//
+//	{file} 文件名 {sheet} sheet名称 {row} 行号 {col} 列号 {coordinates} 单元格坐标
+//	{column} 表头列名 {field} 结构体字段名 {value} 单元格的值 {param} 校验规则的参数
+//	{message} 原始的错误信息
+type MessageCatalog map[ErrorCode]string
+
+// messageCatalogs built-in message catalogs, key is locale
+var messageCatalogs = map[string]MessageCatalog{
+	LocaleEN: {
+		ErrorCodeInvalid:            "row {row} column {column}: {message}",
+		ErrorCodeSheetNotExist:      "sheet {sheet} not exist",
+		ErrorCodeOutputType:         "{message}",
+		ErrorCodeColumnMissing:      "column {column} not exist in header",
+		ErrorCodeColumnRepeat:       "column {column} repeat in header",
+		ErrorCodeColumnUnknown:      "column {column} is unknown",
+		ErrorCodeRequired:           "row {row} column {column}: value is required",
+		ErrorCodeTypeMismatch:       "row {row} column {column}: value {value} is invalid",
+		ErrorCodeTypeNotSupport:     "column {column}: field type not support",
+		ErrorCodeTagInvalid:         "field {field}: tag {param} is invalid",
+		ErrorCodeSerializerNotExist: "column {column}: serializer not exist",
+		ErrorCodeMin:                "row {row} column {column}: value must not be less than {param}",
+		ErrorCodeMax:                "row {row} column {column}: value must not be greater than {param}",
+		ErrorCodeLen:                "row {row} column {column}: value length must be {param}",
+		ErrorCodeRegex:              "row {row} column {column}: value {value} is invalid",
+		ErrorCodeOneOf:              "row {row} column {column}: value must be one of {param}",
+		ErrorCodeEmail:              "row {row} column {column}: {value} is not a valid email",
+		ErrorCodeURL:                "row {row} column {column}: {value} is not a valid url",
+		ErrorCodeForeignKey:         "row {row} column {column}: {value} not exist in parent sheet",
+	},
+	LocaleZH: {
+		ErrorCodeInvalid:            "第{row}行 {column} 列: {message}",
+		ErrorCodeSheetNotExist:      "工作表{sheet}不存在",
+		ErrorCodeOutputType:         "{message}",
+		ErrorCodeColumnMissing:      "表头缺少{column}列",
+		ErrorCodeColumnRepeat:       "表头{column}列重复",
+		ErrorCodeColumnUnknown:      "表头{column}列无法识别",
+		ErrorCodeRequired:           "第{row}行 {column} 列: 值不能为空",
+		ErrorCodeTypeMismatch:       "第{row}行 {column} 列: 值{value}格式不正确",
+		ErrorCodeTypeNotSupport:     "{column} 列: 字段类型不支持",
+		ErrorCodeTagInvalid:         "{field} 字段: 标签{param}无效",
+		ErrorCodeSerializerNotExist: "{column} 列: 序列化器不存在",
+		ErrorCodeMin:                "第{row}行 {column} 列: 值不能小于{param}",
+		ErrorCodeMax:                "第{row}行 {column} 列: 值不能大于{param}",
+		ErrorCodeLen:                "第{row}行 {column} 列: 长度必须为{param}",
+		ErrorCodeRegex:              "第{row}行 {column} 列: 值{value}格式不正确",
+		ErrorCodeOneOf:              "第{row}行 {column} 列: 值必须为{param}中的一个",
+		ErrorCodeEmail:              "第{row}行 {column} 列: {value}不是有效的邮箱",
+		ErrorCodeURL:                "第{row}行 {column} 列: {value}不是有效的网址",
+		ErrorCodeForeignKey:         "第{row}行 {column} 列: {value}在主表中不存在",
+	},
+}
+
+// RegisterLocale 注册自定义语言的错误消息模板，locale已存在时覆盖catalog中的错误码
+// catalog中没有的错误码使用英文模板
+func (p *Parser) RegisterLocale(locale string, catalog MessageCatalog) {
+	merged := make(MessageCatalog)
+	for code, template := range p.catalog(locale) {
+		merged[code] = template
+	}
+	for code, template := range catalog {
+		merged[code] = template
+	}
+
+	if p.locales == nil {
+		p.locales = make(map[string]MessageCatalog)
+	}
+	p.locales[locale] = merged
+}
+
+// catalog the message catalog of the locale, registered locales take priority
+func (p *Parser) catalog(locale string) MessageCatalog {
+	if catalog, ok := p.locales[locale]; ok {
+		return catalog
+	}
+	return messageCatalogs[locale]
+}
+
+// ErrorMessage 使用Parser.Locale的模板格式化错误信息，面向最终用户，不包含文件名
+// 不是*Error或者不在单元格上的未知错误返回原始的错误信息
+func (p *Parser) ErrorMessage(err error) string {
+	e, ok := err.(*Error)
+	if !ok {
+		return err.Error()
+	}
+	if e.Code == ErrorCodeInvalid && e.RowIndex == 0 && e.Column == "" {
+		return e.Err.Error()
+	}
+
+	locale := p.Locale
+	if locale == "" {
+		locale = LocaleEN
+	}
+	template, ok := p.catalog(locale)[e.Code]
+	if !ok {
+		template, ok = messageCatalogs[LocaleEN][e.Code]
+	}
+	if !ok {
+		template = messageCatalogs[LocaleEN][ErrorCodeInvalid]
+	}
+
+	return strings.NewReplacer(
+		"{file}", e.FileName,
+		"{sheet}", e.SheetName,
+		"{row}", strconv.Itoa(e.RowIndex),
+		"{col}", strconv.Itoa(e.ColIndex),
+		"{coordinates}", e.Coordinates,
+		"{column}", e.Column,
+		"{field}", e.Field,
+		"{value}", e.Value,
+		"{param}", e.Param,
+		"{message}", e.Err.Error(),
+	).Replace(template)
+}
+
+// ErrorMessages err中所有*Error格式化后的错误信息，支持*Report和multierror
+func (p *Parser) ErrorMessages(err error) []string {
+	var messages []string
+	WalkErrors(err, func(e *Error) bool {
+		messages = append(messages, p.ErrorMessage(e))
+		return true
+	})
+	return messages
+}
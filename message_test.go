@@ -0,0 +1,48 @@
+package excelstructure
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ErrorMessage(t *testing.T) {
+	fileName := newMemberFile(t)
+	p := NewParser()
+	var members []Member
+	err := p.Read(fileName, &members)
+	require.Error(t, err)
+
+	messages := p.ErrorMessages(err)
+	require.Equal(t, 12, len(messages))
+	require.Equal(t, "row 3 column name: value is required", messages[0])
+
+	p.Locale = LocaleZH
+	messages = p.ErrorMessages(err)
+	require.Equal(t, "第3行 name 列: 值不能为空", messages[0])
+	require.Equal(t, "第3行 gender 列: 值必须为male|female中的一个", messages[4])
+
+	// custom locale, missing codes use the english template
+	p.RegisterLocale("ja", MessageCatalog{
+		ErrorCodeRequired: "{row}行目 {column}({field}, {coordinates}): 必須項目です",
+	})
+	p.Locale = "ja"
+	messages = p.ErrorMessages(err)
+	require.Equal(t, "3行目 name(Name, A3): 必須項目です", messages[0])
+	require.Equal(t, "row 3 column age: value must not be less than 18", messages[1])
+
+	// override the built-in template
+	p.RegisterLocale(LocaleZH, MessageCatalog{ErrorCodeRequired: "{column}必填"})
+	p.Locale = LocaleZH
+	messages = p.ErrorMessages(err)
+	require.Equal(t, "name必填", messages[0])
+	require.Equal(t, "第3行 age 列: 值不能小于18", messages[1])
+	// registered locale does not affect other parsers
+	other := NewParser()
+	other.Locale = LocaleZH
+	require.Equal(t, "第3行 name 列: 值不能为空", other.ErrorMessage(ErrorList(err)[0]))
+
+	require.Equal(t, "custom", p.ErrorMessage(errors.New("custom")))
+	require.Equal(t, "open failed", p.ErrorMessage(NewError(fileName, "", "", errors.New("open failed"))))
+}
@@ -0,0 +1,90 @@
+package excelstructure
+
+import (
+	"reflect"
+	"strconv"
+)
+
+const (
+	// MetaRow meta:row 行号
+	MetaRow = "row"
+	// MetaSheet meta:sheet sheet名称
+	MetaSheet = "sheet"
+	// MetaFile meta:file 文件名
+	MetaFile = "file"
+	// MetaCoordinates meta:coordinates:<column> 列的单元格坐标
+	MetaCoordinates = "coordinates"
+)
+
+// isMetaType whether the field type is supported by the meta kind, the row is set to int, uint or string field,
+// the others are set to string field, false if the meta kind is unknown
+func isMetaType(meta string, fieldType reflect.Type) bool {
+	switch meta {
+	case MetaRow:
+		switch fieldType.Kind() {
+		case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return true
+		}
+		return false
+	case MetaSheet, MetaFile, MetaCoordinates:
+		return fieldType.Kind() == reflect.String
+	default:
+		return false
+	}
+}
+
+// resolveMetaColumns resolve the column of meta:coordinates to the column of the field, the column name and aliases
+// of the fields are resolved like the header, the other columns such as extra columns are kept
+func resolveMetaColumns(structType reflect.Type, tagMap map[string]TagSetting) {
+	var resolver *columnResolver
+	for fieldName, tagSetting := range tagMap {
+		if tagSetting.Meta != MetaCoordinates || tagSetting.MetaColumn == "" {
+			continue
+		}
+		if resolver == nil {
+			resolver = newColumnResolver(structType, tagMap, false)
+		}
+		if column, ok := resolver.resolve(tagSetting.MetaColumn); ok {
+			tagSetting.MetaColumn = column
+			tagMap[fieldName] = tagSetting
+		}
+	}
+}
+
+// setMetaField set the row information to the meta field, the field type is checked when parsing the tag
+func (p *Parser) setMetaField(
+	ve reflect.Value, field structField, sheetData *SheetData, rowIndex int, tagSetting TagSetting,
+) {
+	var value string
+	switch tagSetting.Meta {
+	case MetaRow:
+		value = strconv.Itoa(rowIndex)
+	case MetaSheet:
+		value = sheetData.SheetName
+	case MetaFile:
+		value = sheetData.FileName
+	case MetaCoordinates:
+		cell, ok := sheetData.Rows[rowIndex][tagSetting.MetaColumn]
+		if !ok {
+			return
+		}
+		value = cell.Coordinates
+	default:
+		return
+	}
+
+	fieldValue, _ := fieldByIndex(ve, field.Index, true)
+	switch fieldValue.Kind() {
+	case reflect.String:
+		fieldValue.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if tagSetting.Meta == MetaRow {
+			fieldValue.SetInt(int64(rowIndex))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if tagSetting.Meta == MetaRow {
+			fieldValue.SetUint(uint64(rowIndex))
+		}
+	}
+}
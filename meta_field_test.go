@@ -0,0 +1,79 @@
+package excelstructure
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type Employee struct {
+	Name      string `excel:"column:name"`
+	Age       int    `excel:"column:age"`
+	Row       int    `excel:"meta:row"`
+	Sheet     string `excel:"meta:sheet"`
+	File      string `excel:"meta:file"`
+	AgeCell   string `excel:"meta:coordinates:age"`
+	RowString string `excel:"meta:row"`
+}
+
+func Test_ReadMetaField(t *testing.T) {
+	fileName := newSheetFile(t, [][]string{
+		{"name", "age"},
+		{"booyang", "18"},
+		{"boo", "20"},
+	})
+
+	p := NewParser()
+	p.StrictHeader = true
+	var employees []Employee
+	require.NoError(t, p.Read(fileName, &employees))
+	require.Equal(t, []Employee{
+		{Name: "booyang", Age: 18, Row: 2, Sheet: "Sheet1", File: fileName, AgeCell: "B2", RowString: "2"},
+		{Name: "boo", Age: 20, Row: 3, Sheet: "Sheet1", File: fileName, AgeCell: "B3", RowString: "3"},
+	}, employees)
+
+	// meta fields are not written
+	outFileName := filepath.Join(t.TempDir(), "employee.xlsx")
+	require.NoError(t, p.Write(outFileName, "", employees))
+	data, err := NewParser().Parse(outFileName)
+	require.NoError(t, err)
+	require.Equal(t, []string{"name", "age"}, data.SheetNameData["Employees"].FieldKeys)
+}
+
+type Visitor struct {
+	Name     string  `excel:"column:name|姓名"`
+	NameCell string  `excel:"meta:coordinates:姓名"`
+	Rows     int     `excel:"meta:rows"`
+	Sheet    *string `excel:"meta:sheet"`
+	Row      float64 `excel:"meta:row"`
+	Cell     string  `excel:"meta:coordinates"`
+}
+
+type Guest struct {
+	Name     string `excel:"column:name|姓名"`
+	NameCell string `excel:"meta:coordinates:姓名"`
+}
+
+func Test_MetaFieldInvalid(t *testing.T) {
+	fileName := newSheetFile(t, [][]string{{"姓名"}, {"booyang"}})
+
+	var visitors []Visitor
+	err := NewParser().Read(fileName, &visitors)
+	require.ErrorIs(t, err, ErrorTagInvalid)
+	fields := make([]string, 0, 4)
+	for _, e := range ErrorList(err) {
+		fields = append(fields, e.Field)
+	}
+	require.Equal(t, []string{"Rows", "Sheet", "Row", "Cell"}, fields)
+
+	// the column of coordinates is resolved by the alias and the column mapping
+	var guests []Guest
+	require.NoError(t, NewParser().Read(fileName, &guests))
+	require.Equal(t, []Guest{{Name: "booyang", NameCell: "A2"}}, guests)
+
+	fileName = newSheetFile(t, [][]string{{"id", "guest"}, {"1", "boo"}})
+	guests = nil
+	require.NoError(t, NewParser().WithColumnMapping(ColumnMap{"Name": "guest"}).Read(fileName, &guests))
+	require.Equal(t, []Guest{{Name: "boo", NameCell: "B2"}}, guests)
+}
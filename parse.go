@@ -2,6 +2,9 @@ package excelstructure
 
 import (
 	"fmt"
+	"io"
+	"reflect"
+	"time"
 
 	sliceutil "github.com/booyangcc/utils/sliceutil"
 	"github.com/xuri/excelize/v2"
@@ -23,10 +26,25 @@ var boolTrueValue = []string{
 // Parser parser.
 type Parser struct {
 	fileName string
-	// DataIndexOffset 数据索引偏移量,第一行可能为表头，则偏移量为1，如果有注释占用一行，则为2
+	// DataIndexOffset 数据索引偏移量,从表头行开始计算,表头占一行则偏移量为1，如果表头下有注释占用一行，则为2
 	DataIndexOffset int
+	// HeaderRowIndex 表头行索引，从1开始，第一行为表头，则索引为1，表头上方有标题等内容时可指定
+	HeaderRowIndex int
+	// HeaderScanRows 大于0时，读取到结构体时在前N行中查找与结构体列名匹配最多的行作为表头
+	HeaderScanRows int
+	// NoHeader sheet没有表头，以列字母A、B、C作为表头，数据从第DataIndexOffset行开始
+	// 读取到结构体时字段使用col或index标签绑定列，或者以列字母作为列名，如column:C
+	NoHeader bool
+	// SheetSettings 按sheet名称设置表头，key为sheetName，覆盖Parser的表头设置
+	SheetSettings map[string]SheetSetting
+	// IgnoreLayout 忽略写入时记录在excel中的表头、注释行和数据起始行，使用Parser的设置
+	IgnoreLayout bool
 	// BoolTrueValues bool类型的true可选值 boolTrueValue
 	BoolTrueValues []string
+	// TimeFormats time.Time字段未指定format标签时依次尝试的解析格式，都不匹配时按excel日期序列号解析
+	TimeFormats []string
+	// Location time.Time字段读写使用的时区，为nil则为time.Local
+	Location *time.Location
 	// IsCheckEmpty 序列化到结构提的时候是否检测空值如果为空值则报错
 	IsCheckEmpty bool
 	// IsEmptyFunc 空值校验函数 可自定义空值
@@ -38,14 +56,30 @@ type Parser struct {
 	ExcelData        *Data
 	// AllowFieldRepeat 允许表头字段重复
 	AllowFieldRepeat bool
+	// FailFast 读取到结构体时遇到第一个错误即停止，默认收集所有错误
+	FailFast bool
+	// StrictHeader 读取到结构体时表头中没有对应结构体字段的列报告为错误
+	StrictHeader bool
+	// NormalizeHeader 读取到结构体时表头与列名和别名归一化后匹配，忽略大小写、空白、下划线和全角半角的差异
+	NormalizeHeader bool
+	// ColumnMapping 运行时字段到列名的映射，覆盖结构体标签的column，ColumnMap或者ColumnMappingFunc
+	// 单次读写使用WithColumnMapping
+	ColumnMapping ColumnMapping
+	// Locale 错误信息的语言，LocaleEN或者LocaleZH，也可以是RegisterLocale注册的语言，默认为LocaleEN
+	Locale           string
 	currentSheetName string
 
-	errsMap    map[string]error
-	hasComment bool
-	// fieldHeadRowIndex 表头行索引，第一行为表头，则索引为1
-	fieldHeadRowIndex int
-	excelFile         *excelize.File
-	serializers       map[string]Serializer
+	excelFile   *excelize.File
+	serializers map[string]Serializer
+	converters  map[reflect.Type]typeConverter
+	// locales message catalogs registered by RegisterLocale
+	locales map[string]MessageCatalog
+	// styles number format style id of the writing file, key is number format
+	styles map[string]int
+	// sheets the sheet names created in the writing file
+	sheets map[string]struct{}
+	// schemas struct tag settings of the sheets to read, key is sheetName, empty is the first sheet
+	schemas map[string]sheetSchema
 }
 
 // NewParser 传入文件名
@@ -56,13 +90,13 @@ type Parser struct {
 //		ExcelDefault: if excel field is empty, use this default value
 func NewParser() *Parser {
 	return &Parser{
-		DataIndexOffset:   1,
-		fieldHeadRowIndex: 1,
-		BoolTrueValues:    boolTrueValue,
+		DataIndexOffset: 1,
+		HeaderRowIndex:  1,
+		BoolTrueValues:  boolTrueValue,
+		TimeFormats:     timeFormats,
 		IsEmptyFunc: func(v string) bool {
 			return v == ""
 		},
-		errsMap: make(map[string]error),
 	}
 }
 
@@ -86,11 +120,27 @@ func (p *Parser) RegisterSerializer(name string, serializer Serializer) error {
 
 // Parse parse.
 func (p *Parser) Parse(fileName string) (*Data, error) {
-	p.fileName = fileName
 	excelFile, err := excelize.OpenFile(fileName)
 	if err != nil {
 		return nil, NewError(fileName, "", "", err)
 	}
+
+	return p.parseFile(fileName, excelFile)
+}
+
+// ParseReader parse from reader, such as http upload file.
+// fileName is the logical file name, only used in Data.FileName and Error.FileName
+func (p *Parser) ParseReader(r io.Reader, fileName string) (*Data, error) {
+	excelFile, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, NewError(fileName, "", "", err)
+	}
+
+	return p.parseFile(fileName, excelFile)
+}
+
+func (p *Parser) parseFile(fileName string, excelFile *excelize.File) (*Data, error) {
+	p.fileName = fileName
 	defer func() {
 		if err1 := excelFile.Close(); err1 != nil {
 			fmt.Println(err1.Error())
@@ -98,12 +148,6 @@ func (p *Parser) Parse(fileName string) (*Data, error) {
 	}()
 
 	p.excelFile = excelFile
-	if p.fieldHeadRowIndex < 1 {
-		p.fieldHeadRowIndex = 1
-	}
-	if p.DataIndexOffset < 1 {
-		p.DataIndexOffset = 1
-	}
 
 	excelData, err := p.getExcelFileData()
 	if err != nil {
@@ -121,6 +165,8 @@ func (p *Parser) getExcelFileData() (*Data, error) {
 		return nil, NewError(fileName, "", "", ErrorNoSheet)
 	}
 
+	sheetList := p.excelFile.GetSheetList()
+	layouts := readLayouts(p.excelFile)
 	sheetIndexData := make(map[int]*SheetData)
 	sheetNameData := make(map[string]*SheetData)
 	for sheetIndex, sheetName := range sheetMap {
@@ -128,50 +174,86 @@ func (p *Parser) getExcelFileData() (*Data, error) {
 		if err != nil {
 			return nil, NewError(fileName, sheetName, fmt.Sprintf("sheet index %d", sheetIndex), err)
 		}
-		if len(rows) == 0 {
+
+		isFirstSheet := len(sheetList) > 0 && sheetList[0] == sheetName
+		resolver := p.columnResolver(sheetName, isFirstSheet)
+		// 读取到结构体指针的sheet为纵向的键值布局
+		if schema, ok := p.schema(sheetName, isFirstSheet); ok && schema.vertical {
+			sheetData := p.verticalSheetData(sheetName, rows, resolver)
+			sheetIndexData[sheetIndex] = sheetData
+			sheetNameData[sheetName] = sheetData
+			continue
+		}
+
+		setting := p.sheetSetting(sheetName, layouts)
+		headerRowIndex := setting.HeaderRowIndex
+		if setting.HeaderScanRows > 0 && !setting.NoHeader {
+			if detected := detectHeaderRow(rows, setting.HeaderScanRows, resolver); detected > 0 {
+				headerRowIndex = detected
+			}
+		}
+		// 数据偏移量从表头行开始计算
+		dataIndexOffset := setting.DataIndexOffset + headerRowIndex - 1
+		if setting.NoHeader {
+			// 没有表头时数据从第DataIndexOffset行开始
+			headerRowIndex = 0
+			dataIndexOffset = setting.DataIndexOffset - 1
+		}
+
+		if len(rows) < headerRowIndex {
 			sheetData := &SheetData{
 				SheetName:       sheetName,
 				FileName:        fileName,
-				DataIndexOffset: p.DataIndexOffset,
+				HeaderRowIndex:  headerRowIndex,
+				DataIndexOffset: dataIndexOffset,
 			}
 			sheetIndexData[sheetIndex] = sheetData
 			sheetNameData[sheetName] = sheetData
 			continue
 		}
-		// 输入数据为excel直观的行数 从1开始
-		sheetFields := rows[p.fieldHeadRowIndex-1]
-
-		fieldValid := make([]string, 0)
-		repeatFieldName := ""
-		for _, fieldName := range sheetFields {
-			if sliceutil.InSlice(fieldName, fieldValid) {
-				repeatFieldName = fieldName
-				break
-			}
-			fieldValid = append(fieldValid, fieldName)
+		var sheetFields []string
+		if setting.NoHeader {
+			// 没有表头时以列字母作为表头
+			sheetFields = columnLetters(maxRowLength(rows))
+		} else {
+			// 输入数据为excel直观的行数 从1开始
+			sheetFields = rows[headerRowIndex-1]
+		}
+		// 读取到结构体时按列名、别名和归一化匹配表头，匹配的表头替换为结构体的列名
+		if resolver != nil {
+			sheetFields = resolver.resolveHeader(sheetFields)
 		}
-		// 检查是否有相同字段
-		if !p.AllowFieldRepeat && repeatFieldName != "" {
+
+		// 检查是否有相同字段，读取到结构体的sheet在表头检查时报告所有重复的列
+		if !p.AllowFieldRepeat && resolver == nil && findRepeatField(sheetFields) != "" {
 			return nil, NewError(fileName, sheetName, fmt.Sprintf("sheet index %d", sheetIndex), ErrorFieldRepeat)
 		}
 
 		parseRows := make(map[int]map[string]*Cell, 0)
+		rowIndexes := make([]int, 0, len(rows))
 		for index, row := range rows {
 			excelIndex := index + 1
-			if excelIndex <= p.DataIndexOffset {
+			if excelIndex <= dataIndexOffset {
 				continue
 			}
 			p.getRow(excelIndex, row, sheetFields, parseRows)
+			rowIndexes = append(rowIndexes, excelIndex)
 		}
 
+		dataTotal := len(rows) - dataIndexOffset
+		if dataTotal < 0 {
+			dataTotal = 0
+		}
 		sheetData := &SheetData{
 			RowTotal:        len(rows),
-			DataTotal:       len(rows) - p.DataIndexOffset,
+			DataTotal:       dataTotal,
 			SheetName:       sheetName,
 			FileName:        fileName,
 			Rows:            parseRows,
+			RowIndexes:      rowIndexes,
 			FieldKeys:       sheetFields,
-			DataIndexOffset: p.DataIndexOffset,
+			HeaderRowIndex:  headerRowIndex,
+			DataIndexOffset: dataIndexOffset,
 		}
 
 		sheetIndexData[sheetIndex] = sheetData
@@ -183,7 +265,7 @@ func (p *Parser) getExcelFileData() (*Data, error) {
 		SheetIndexData: sheetIndexData,
 		SheetNameData:  sheetNameData,
 		SheetTotal:     p.excelFile.SheetCount,
-		SheetList:      p.excelFile.GetSheetList(),
+		SheetList:      sheetList,
 	}
 
 	return excelData, nil
@@ -222,3 +304,15 @@ func (p *Parser) getRow(rowIndex int, rawRow, sheetFields []string, rowsData map
 
 	rowsData[rowIndex] = rowData
 }
+
+// findRepeatField return the first repeated field name, empty if no repeat.
+func findRepeatField(sheetFields []string) string {
+	fieldValid := make([]string, 0, len(sheetFields))
+	for _, fieldName := range sheetFields {
+		if sliceutil.InSlice(fieldName, fieldValid) {
+			return fieldName
+		}
+		fieldValid = append(fieldValid, fieldName)
+	}
+	return ""
+}
@@ -1,7 +1,9 @@
 package excelstructure
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -23,3 +25,66 @@ func Test_GetIntValue1(t *testing.T) {
 	assert.NoError(t, err)
 	require.Equal(t, 3, row3)
 }
+
+func Test_ParseReaderErrorFileName(t *testing.T) {
+	p := NewParser()
+	_, err := p.ParseReader(strings.NewReader("not a xlsx file"), "upload.xlsx")
+	require.Error(t, err)
+	var e *Error
+	require.True(t, errors.As(err, &e))
+	require.Equal(t, "upload.xlsx", e.FileName)
+}
+
+// newBannerFile create a file with a title banner and a blank line above the header
+func newBannerFile(t *testing.T) string {
+	return newSheetFile(t, [][]string{
+		{"user list of 2023"},
+		{},
+		{"user_name", "phone", "age", "man"},
+		{"booyang", "123456789", "18", "yes"},
+		{"bob", "", "17", "no"},
+	})
+}
+
+func Test_ParseWithHeaderRowIndex(t *testing.T) {
+	fileName := newBannerFile(t)
+	p := NewParser()
+	p.HeaderRowIndex = 3
+	data, err := p.Parse(fileName)
+	require.NoError(t, err)
+
+	s := data.SheetNameData["Sheet1"]
+	require.Equal(t, []string{"user_name", "phone", "age", "man"}, s.FieldKeys)
+	require.Equal(t, []int{4, 5}, s.RowIndexes)
+	age, err := s.GetIntValue(4, "age")
+	assert.NoError(t, err)
+	require.Equal(t, 18, age)
+
+	_, err = s.GetCell(3, "age")
+	require.ErrorIs(t, err.(*Error).Err, ErrorRowIndexIsHeader)
+	_, err = s.GetCell(2, "age")
+	require.ErrorIs(t, err.(*Error).Err, ErrorDataRowOutOfRange)
+}
+
+func Test_ReadWithSheetSettingAndHeaderScan(t *testing.T) {
+	fileName := newBannerFile(t)
+	p := NewParser()
+	p.SheetSettings = map[string]SheetSetting{
+		"Sheet1": {HeaderRowIndex: 3},
+	}
+	var infos []*BaseInfo
+	err := p.Read(fileName, &infos)
+	assert.NoError(t, err)
+	require.Equal(t, 2, len(infos))
+	require.Equal(t, "booyang", infos[0].Name)
+	require.False(t, infos[1].Man)
+
+	p = NewParser()
+	p.HeaderScanRows = 5
+	infos = nil
+	err = p.Read(fileName, &infos)
+	assert.NoError(t, err)
+	require.Equal(t, 2, len(infos))
+	require.Equal(t, "bob", infos[1].Name)
+	require.Equal(t, 3, p.ExcelData.SheetNameData["Sheet1"].HeaderRowIndex)
+}
@@ -2,6 +2,7 @@ package excelstructure
 
 import (
 	"fmt"
+	"io"
 	"reflect"
 	"strconv"
 
@@ -11,48 +12,90 @@ import (
 
 // ReadWithSheetName parse with sheet index. start with 1
 func (p *Parser) ReadWithSheetName(fileName, sheetName string, output interface{}) error {
+	return p.ReadWithMultiSheet(fileName, map[string]interface{}{
+		sheetName: output,
+	})
+}
+
+// ReadWithMultiSheet parse with sheetDataMap, key is sheetName, value is output, output must be a pointer slice,
+// or a struct pointer to read the key-value sheet, column A is the keys and column B is the values
+func (p *Parser) ReadWithMultiSheet(fileName string, sheetDataMap map[string]interface{}) error {
+	p.setSchemas(sheetDataMap)
+	defer func() {
+		p.schemas = nil
+	}()
+
 	excelData, err := p.Parse(fileName)
 	if err != nil {
 		return err
 	}
 
-	return p.readToStruct(sheetName, excelData, output)
+	return p.readMultiSheet(excelData, sheetDataMap)
 }
 
-// ReadWithMultiSheet parse with sheetDataMap, key is sheetName, value is output, output must be a pointer slice
-func (p *Parser) ReadWithMultiSheet(fileName string, sheetDataMap map[string]interface{}) error {
-	excelData, err := p.Parse(fileName)
+// ReadFromReader read the first sheet from reader, fileName is the logical file name used in errors
+// output must be a pointer slice or a struct pointer
+func (p *Parser) ReadFromReader(r io.Reader, fileName string, output interface{}) error {
+	return p.ReadFromReaderWithSheetName(r, fileName, "", output)
+}
+
+// ReadFromReaderWithSheetName read the sheet from reader, fileName is the logical file name used in errors
+func (p *Parser) ReadFromReaderWithSheetName(r io.Reader, fileName, sheetName string, output interface{}) error {
+	return p.ReadFromReaderWithMultiSheet(r, fileName, map[string]interface{}{
+		sheetName: output,
+	})
+}
+
+// ReadFromReaderWithMultiSheet read multi sheet from reader, key is sheetName, value is output
+func (p *Parser) ReadFromReaderWithMultiSheet(r io.Reader, fileName string, sheetDataMap map[string]interface{}) error {
+	p.setSchemas(sheetDataMap)
+	defer func() {
+		p.schemas = nil
+	}()
+
+	excelData, err := p.ParseReader(r, fileName)
 	if err != nil {
 		return err
 	}
-	fmt.Println(excelData.SheetList)
-	for name, output := range sheetDataMap {
-		err = p.readToStruct(name, excelData, output)
-		if err != nil {
-			return err
+
+	return p.readMultiSheet(excelData, sheetDataMap)
+}
+
+// readMultiSheet read sheets in the workbook order, the errors of all sheets are collected in one report
+func (p *Parser) readMultiSheet(excelData *Data, sheetDataMap map[string]interface{}) error {
+	names := make([]string, 0, len(sheetDataMap))
+	if _, ok := sheetDataMap[""]; ok {
+		names = append(names, "")
+	}
+	for _, name := range excelData.SheetList {
+		if _, ok := sheetDataMap[name]; ok {
+			names = append(names, name)
 		}
 	}
-	return nil
+	for name := range sheetDataMap {
+		if name != "" && !sliceutil.InSlice(name, excelData.SheetList) {
+			names = append(names, name)
+		}
+	}
+
+	report := newReport(p.fileName)
+	for _, name := range names {
+		p.readToStruct(name, excelData, sheetDataMap[name], report)
+		if p.FailFast && report.HasError() {
+			break
+		}
+	}
+	return report.errorOrNil()
 }
 
 // Parser parse with sheet index 1
-// output must be a pointer slice
+// output must be a pointer slice or a struct pointer
 // if the pointer field is pointer, and the value is empty ,the pointer field will be nil
 func (p *Parser) Read(fileName string, output interface{}) error {
-	excelData, err := p.Parse(fileName)
-	if err != nil {
-		return err
-	}
-	return p.readToStruct("", excelData, output)
+	return p.ReadWithSheetName(fileName, "", output)
 }
 
-func (p *Parser) readToStruct(sheetName string, excelData *Data, output interface{}) (errs error) {
-	if !sliceutil.InSlice(sheetName, excelData.SheetList) {
-		errs = multierror.Append(errs,
-			NewError(p.fileName, "", fmt.Sprintf("sheetName %s", sheetName), ErrorSheetName))
-		return
-	}
-
+func (p *Parser) readToStruct(sheetName string, excelData *Data, output interface{}, report *Report) {
 	if sheetName == "" {
 		if len(excelData.SheetList) == 0 {
 			return
@@ -60,14 +103,23 @@ func (p *Parser) readToStruct(sheetName string, excelData *Data, output interfac
 		sheetName = excelData.SheetList[0]
 	}
 
-	p.currentSheetName = p.excelFile.GetSheetList()[0]
+	if !sliceutil.InSlice(sheetName, excelData.SheetList) {
+		report.add(sheetName, nil, 0,
+			NewError(p.fileName, "", fmt.Sprintf("sheetName %s", sheetName), ErrorSheetName))
+		return
+	}
+
+	p.currentSheetName = sheetName
 
 	sheetData := excelData.SheetNameData[sheetName]
 
 	rv := reflect.ValueOf(output)
-	if rv.Kind() == reflect.Ptr && rv.Elem().Kind() != reflect.Slice {
-		errs = multierror.Append(errs,
-			NewError(p.fileName, p.currentSheetName, "", ErrorInOutputType))
+	if _, ok := getOutputStructType(output); ok {
+		p.readVertical(sheetData, rv, report)
+		return
+	}
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		report.add(sheetName, nil, 0, NewError(p.fileName, p.currentSheetName, "", ErrorInOutputType))
 		return
 	}
 
@@ -75,115 +127,190 @@ func (p *Parser) readToStruct(sheetName string, excelData *Data, output interfac
 	sliceElemType := sliceType.Elem()
 	sliceElemStructType, err := getSliceElemType(p.fileName, p.currentSheetName, rv)
 	if err != nil {
-		errs = multierror.Append(errs, err)
+		report.add(sheetName, nil, 0, err)
+		return
+	}
+	tagMap, err := p.fieldTagSetting(sliceElemStructType)
+	if err != nil {
+		report.add(sheetName, nil, 0, err)
+		return
+	}
+
+	// 表头的错误只报告一次，不再解析数据行
+	if p.checkHeader(sheetData, sliceElemStructType, tagMap, report) {
 		return
 	}
-	tagMap := parseFieldTagSetting(sliceElemStructType)
 
-	arr := reflect.MakeSlice(sliceType, 0, 0)
-	for i := range sheetData.Rows {
-		out := reflect.New(sliceElemStructType)
+	outs, rowIndexes := p.decodeRows(sheetData, sliceElemStructType, tagMap, report)
+	if !p.FailFast || !report.HasError() {
+		p.readChildren(excelData, sheetData, sliceElemStructType, outs, rowIndexes, tagMap, report,
+			map[string]bool{sheetData.SheetName: true})
+	}
 
-		if err := p.parseRowToStruct(i, sheetData, out, tagMap); err != nil {
-			p.appendError(errs, err)
-			continue
-		}
+	arr := reflect.MakeSlice(sliceType, 0, len(outs))
+	for _, out := range outs {
 		if sliceElemType.Kind() == reflect.Ptr {
 			arr = reflect.Append(arr, out)
 		}
 		if sliceElemType.Kind() == reflect.Struct {
 			arr = reflect.Append(arr, out.Elem())
 		}
-
 	}
 	rv.Elem().Set(arr)
-
-	return
 }
 
-func (p *Parser) appendError(errs error, err error) {
-	if errs == nil {
-		return
-	}
-
-	if err == nil {
-		return
-	}
-
-	if p.errsMap == nil {
-		p.errsMap = make(map[string]error)
-	}
-
-	if _, ok := p.errsMap[err.Error()]; ok {
-		return
+// getOutputElemType get the struct type of output slice elem, output must be a pointer slice
+func getOutputElemType(output interface{}) (reflect.Type, error) {
+	rv := reflect.ValueOf(output)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+		return nil, ErrorInOutputType
 	}
 
-	errs = multierror.Append(errs, err)
-	p.errsMap[err.Error()] = err
+	return getSliceElemType("", "", rv)
 }
 
 // parse row to struct by tag setting
 func (p *Parser) parseRowToStruct(
 	rowIndex int, sheetData *SheetData, ve reflect.Value, tagMap map[string]TagSetting,
-) (err error) {
+) error {
 	if ve.Kind() != reflect.Ptr {
-		return NewError(p.fileName, p.currentSheetName, fmt.Sprintf("row %d", rowIndex), ErrorTypePointer)
+		return newRowError(p.fileName, p.currentSheetName, rowIndex, "", ErrorTypePointer)
 	}
 
 	if !ve.IsValid() {
-		return NewError(p.fileName, p.currentSheetName, fmt.Sprintf("row %d", rowIndex), ErrorFieldInvalid)
+		return newRowError(p.fileName, p.currentSheetName, rowIndex, "", ErrorFieldInvalid)
 	}
 
+	var errs error
 	vek := reflect.Indirect(ve)
-	createStruct := vek.Type()
-	for i := 0; i < createStruct.NumField(); i++ {
-		field := createStruct.Field(i)
-		tag := field.Tag
-		et := tag.Get(TagName)
-		columnName, df, skip, serializer := field.Name, "", false, "json"
-		if len(et) > 0 {
-			val, ok := tagMap[field.Name]
-			if ok {
-				columnName = val.Column
-				df = val.Default
-				skip = val.Skip
-				serializer = val.Serializer
-			}
+	fields := structFields(vek.Type(), tagMap)
+	var columns map[string]struct{}
+	for _, field := range fields {
+		tagSetting, ok := tagMap[field.Name]
+		if !ok {
+			tagSetting = TagSetting{Column: field.Name}
 		}
-		if skip {
+		if tagSetting.Meta != "" {
+			p.setMetaField(vek, field, sheetData, rowIndex, tagSetting)
 			continue
 		}
-
-		cell, err1 := sheetData.GetCell(rowIndex, columnName)
-		if err1 != nil {
-			return err1
+		if tagSetting.Skip {
+			continue
 		}
-
-		if p.IsCheckEmpty && cell.IsEmpty {
-			return NewError(p.fileName, p.currentSheetName, cell.Coordinates, ErrorFieldValueEmpty)
+		if tagSetting.Extra {
+			if columns == nil {
+				columns = boundColumns(fields, tagMap, sheetData.FieldKeys)
+			}
+			p.setExtraField(vek, field, sheetData, rowIndex, columns)
+			continue
+		}
+		if tagSetting.Repeat {
+			if err := p.setRepeatField(vek, field, sheetData, rowIndex, tagSetting); err != nil {
+				errs = multierror.Append(errs, err)
+				if p.FailFast {
+					return errs
+				}
+			}
+			continue
 		}
 
-		fieldValue := ve.Elem().FieldByName(field.Name)
 		fieldType := field.Type
 		if fieldType.Kind() == reflect.Ptr {
 			fieldType = fieldType.Elem()
 		}
-		fieldTypeKind := uint(fieldType.Kind())
-		if (uint(reflect.Invalid) < fieldTypeKind && fieldTypeKind < uint(reflect.Float64)) ||
-			fieldTypeKind == uint(reflect.String) {
-			err = p.fieldSetAll(fieldValue, cell, df)
-			if err != nil {
-				return err
+
+		// 表头没有的可选列不校验，字段为默认值或者零值
+		if tagSetting.Optional && !sliceutil.InSlice(tagSetting.Column, sheetData.FieldKeys) {
+			cell := &Cell{RowIndex: rowIndex, Key: tagSetting.Column, IsEmpty: true}
+			// 内联的结构体指针为nil且没有值时保持nil
+			fieldValue, ok := fieldByIndex(vek, field.Index, tagSetting.Default != "")
+			if !ok {
+				continue
 			}
-		} else {
-			err = p.fieldUmarshal(fieldValue, cell, serializer)
-			if err != nil {
-				return err
+			if err := p.setField(fieldValue, fieldType, cell, tagSetting); err != nil {
+				setErrorField(err, field.Name, tagSetting.Column, "")
+				errs = multierror.Append(errs, err)
+				if p.FailFast {
+					return errs
+				}
 			}
+			continue
 		}
 
+		// 单元格的错误不中断，收集当前行所有单元格的错误
+		cell, err := sheetData.GetCell(rowIndex, tagSetting.Column)
+		if err != nil {
+			setErrorField(err, field.Name, tagSetting.Column, "")
+			errs = multierror.Append(errs, err)
+			if p.FailFast {
+				return errs
+			}
+			continue
+		}
+
+		value := cell.Value
+		if value == "" {
+			value = tagSetting.Default
+		}
+		if validateErrs := p.validateCell(cell, value, fieldType, tagSetting); len(validateErrs) > 0 {
+			for _, err := range validateErrs {
+				setErrorField(err, field.Name, tagSetting.Column, value)
+			}
+			if p.FailFast {
+				return multierror.Append(errs, validateErrs[0])
+			}
+			errs = multierror.Append(errs, validateErrs...)
+			continue
+		}
+
+		fieldValue, ok := fieldByIndex(vek, field.Index, value != "")
+		if !ok {
+			continue
+		}
+		err = p.setField(fieldValue, fieldType, cell, tagSetting)
+		if err != nil {
+			setErrorField(err, field.Name, tagSetting.Column, value)
+			errs = multierror.Append(errs, err)
+			if p.FailFast {
+				return errs
+			}
+		}
+	}
+	return errs
+}
+
+// setField set the field by cell value, fieldType pointer is dereferenced
+func (p *Parser) setField(fieldValue reflect.Value, fieldType reflect.Type, cell *Cell, tagSetting TagSetting) error {
+	df, serializer := tagSetting.Default, tagSetting.Serializer
+	isBasic := isBasicKind(fieldType.Kind())
+	isSerializer := isExplicitSerializer(serializer, isBasic)
+	converter, isConverter := p.converters[fieldType]
+	// 优先级: 类型转换器, 时间类型, serializer标签, TextUnmarshaler/Scanner, 基础类型, 默认json序列化
+	switch {
+	case isConverter && !isSerializer:
+		return p.fieldSetConverter(fieldValue, cell, df, converter)
+	case isTimeType(fieldType):
+		return p.fieldSetTime(fieldValue, cell, df, tagSetting.Format)
+	case isSerializer:
+		return p.fieldUmarshal(fieldValue, cell, serializer)
+	case isTextUnmarshalType(fieldType):
+		return p.fieldSetText(fieldValue, cell, df)
+	case isBasic:
+		return p.fieldSetAll(fieldValue, cell, df)
+	default:
+		return p.fieldUmarshal(fieldValue, cell, JSONSerializerName)
 	}
-	return err
+}
+
+// isBasicKind kinds set by fieldSet
+func isBasicKind(kind reflect.Kind) bool {
+	return (reflect.Invalid < kind && kind <= reflect.Float64) || kind == reflect.String
+}
+
+// isExplicitSerializer the serializer tag takes priority over the field type,
+// json serializer tag of basic kind field is ignored as before
+func isExplicitSerializer(serializer string, isBasic bool) bool {
+	return serializer != "" && (!IsDefaultSerializer(serializer) || !isBasic)
 }
 
 func (p *Parser) fieldUmarshal(field reflect.Value, cell *Cell, serializerName string) error {
@@ -255,7 +382,6 @@ func (p *Parser) fieldSetAll(field reflect.Value, cell *Cell, defaultValue strin
 
 func (p *Parser) fieldSet(field reflect.Value, value string, cell *Cell) error {
 	if !field.IsValid() {
-		fmt.Println(field.Kind())
 		return NewError(p.fileName, p.currentSheetName, cell.Coordinates, ErrorFieldNotSet)
 	}
 	if !field.CanSet() {
@@ -279,7 +405,12 @@ func (p *Parser) fieldSet(field reflect.Value, value string, cell *Cell) error {
 	case reflect.Bool:
 		field.SetBool(sliceutil.InSlice(value, p.BoolTrueValues))
 	case reflect.Float32, reflect.Float64:
-		floatValue, err := strconv.ParseFloat(value, 32)
+		// 空的浮点数单元格为零值，与之前由json序列化器解析时一致
+		if value == "" {
+			field.SetFloat(0)
+			return nil
+		}
+		floatValue, err := strconv.ParseFloat(value, field.Type().Bits())
 		if err != nil {
 			return NewError(p.fileName, p.currentSheetName, cell.Coordinates, ErrorFieldNotMatch)
 		}
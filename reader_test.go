@@ -1,25 +1,44 @@
 package excelstructure
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// BaseInfo matches the columns of test.xlsx, test_with_comment.xlsx and test_check_empty.xlsx
+type BaseInfo struct {
+	Name  string  `excel:"column:user_name;comment:person name"`
+	Phone *string `excel:"column:phone;comment:phone number"`
+	Age   string  `excel:"column:age;"`
+	Man   bool    `excel:"column:man;default:true"`
+}
+
 func Test_ParseReadWithSheetIndex(t *testing.T) {
-	var info []*Info
+	var info []*BaseInfo
 	p := NewParser()
 	err := p.ReadWithSheetName("./test_excel_file/test.xlsx", "Sheet1", &info)
 	if err != nil {
 		assert.Error(t, err)
 	}
 	require.Equal(t, 4, len(info))
+	require.Equal(t, "booyang", info[0].Name)
+}
+
+func Test_ParseReadWithOtherSheetName(t *testing.T) {
+	var info []*BaseInfo
+	p := NewParser()
+	err := p.ReadWithSheetName("./test_excel_file/test.xlsx", "Sheet2", &info)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(info))
 	require.Equal(t, "booyang1", info[0].Name)
+	require.Equal(t, "Sheet2", p.currentSheetName)
 }
 
 func Test_ParseRead(t *testing.T) {
-	var info []*Info
+	var info []*BaseInfo
 	p := NewParser()
 	err := p.Read("./test_excel_file/test.xlsx", &info)
 	if err != nil {
@@ -32,7 +51,7 @@ func Test_ParseRead(t *testing.T) {
 // excel data index offset is 2, the first two rows are not data,
 // first row is title,second row is comment
 func Test_ParseReadWithComment(t *testing.T) {
-	var info []*Info
+	var info []*BaseInfo
 	p := NewParser()
 	p.DataIndexOffset = 2
 	err := p.Read("./test_excel_file/test_with_comment.xlsx", &info)
@@ -44,7 +63,7 @@ func Test_ParseReadWithComment(t *testing.T) {
 }
 
 func Test_ParseReadWithCheckEmpty(t *testing.T) {
-	var info []*Info
+	var info []*BaseInfo
 	p := NewParser()
 	p.IsCheckEmpty = true
 	err := p.Read("./test_excel_file/test_check_empty.xlsx", &info)
@@ -89,3 +108,33 @@ func Test_ParseReadWithMultiSheet(t *testing.T) {
 	require.Equal(t, "booyang_sheet1", infos1[0].Name)
 	require.Equal(t, "booyang_sheet2", infos2[0].Name)
 }
+
+func Test_ParseReadKeepRowOrder(t *testing.T) {
+	var info []*BaseInfo
+	p := NewParser()
+	err := p.ReadWithSheetName("./test_excel_file/test.xlsx", "Sheet1", &info)
+	assert.NoError(t, err)
+	names := make([]string, 0, len(info))
+	for _, v := range info {
+		names = append(names, v.Name)
+	}
+	require.Equal(t, []string{"booyang", "bob", "tom", "sandy"}, names)
+	require.Equal(t, []int{2, 3, 4, 5}, p.ExcelData.SheetNameData["Sheet1"].RowIndexes)
+}
+
+func Test_ReadFromReader(t *testing.T) {
+	persons := []*Person{
+		{Name: "booyang", Age: 18, Man: true, Address: []string{"beijing"}},
+		{Name: "bob", Age: 17, Man: true, Address: []string{"london"}},
+	}
+	p := NewParser()
+	buf := &bytes.Buffer{}
+	err := p.WriteToWriter(buf, "download.xlsx", "persons", persons)
+	assert.NoError(t, err)
+
+	var newPersons []*Person
+	err = p.ReadFromReader(buf, "upload.xlsx", &newPersons)
+	assert.NoError(t, err)
+	require.Equal(t, persons, newPersons)
+	require.Equal(t, "upload.xlsx", p.ExcelData.FileName)
+}
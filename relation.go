@@ -0,0 +1,290 @@
+package excelstructure
+
+import (
+	"fmt"
+	"reflect"
+
+	sliceutil "github.com/booyangcc/utils/sliceutil"
+	"github.com/hashicorp/go-multierror"
+	"github.com/xuri/excelize/v2"
+)
+
+// isChildrenType the field type of children tag must be a slice of struct or struct pointer
+func isChildrenType(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice {
+		return false
+	}
+	elemType := indirectType(t.Elem())
+	return elemType.Kind() == reflect.Struct && !isTimeType(elemType)
+}
+
+// childrenFields the fields with children tag
+func childrenFields(structType reflect.Type, tagMap map[string]TagSetting) []structField {
+	var fields []structField
+	for _, field := range structFields(structType, tagMap) {
+		if tagMap[field.Name].Children != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// checkNoChildren the key-value sheet is one record without primary key rows to link,
+// the children fields of the struct return ErrorFieldTypeNotSupport
+func (p *Parser) checkNoChildren(structType reflect.Type, tagMap map[string]TagSetting) error {
+	var errs *multierror.Error
+	for _, field := range childrenFields(structType, tagMap) {
+		errs = multierror.Append(errs,
+			newRowError(p.fileName, p.currentSheetName, 0, tagMap[field.Name].Column, ErrorFieldTypeNotSupport))
+	}
+	return errs.ErrorOrNil()
+}
+
+// addChildSchemas record the struct tag settings of the child sheets recursively,
+// the sheets already recorded are not overwritten
+func (p *Parser) addChildSchemas(structType reflect.Type, tagMap map[string]TagSetting) {
+	for _, field := range childrenFields(structType, tagMap) {
+		sheetName := tagMap[field.Name].Children
+		if _, ok := p.schemas[sheetName]; ok {
+			continue
+		}
+		childType := indirectType(field.Type.Elem())
+		childTagMap, err := p.fieldTagSetting(childType)
+		if err != nil {
+			continue
+		}
+		p.schemas[sheetName] = sheetSchema{structType: childType, tagMap: childTagMap}
+		p.addChildSchemas(childType, childTagMap)
+	}
+}
+
+// decodeRows decode the data rows of the sheet, return the struct pointers of the rows without error
+// and their row indexes
+func (p *Parser) decodeRows(
+	sheetData *SheetData, structType reflect.Type, tagMap map[string]TagSetting, report *Report,
+) ([]reflect.Value, []int) {
+	outs := make([]reflect.Value, 0, len(sheetData.RowIndexes))
+	rowIndexes := make([]int, 0, len(sheetData.RowIndexes))
+	for _, i := range sheetData.RowIndexes {
+		out := reflect.New(structType)
+		if err := p.parseRowToStruct(i, sheetData, out, tagMap); err != nil {
+			report.add(sheetData.SheetName, sheetData.FieldKeys, i, err)
+			if p.FailFast {
+				break
+			}
+			continue
+		}
+		outs = append(outs, out)
+		rowIndexes = append(rowIndexes, i)
+	}
+	return outs, rowIndexes
+}
+
+// readChildren read the child sheets of the children fields and attach the child rows to the parents
+// by the foreign key, the child rows whose foreign key not exist in the parent sheet are reported.
+// visiting records the sheets on the way to stop recursive relations
+func (p *Parser) readChildren(
+	excelData *Data, parentData *SheetData, parentType reflect.Type, parents []reflect.Value, parentRows []int,
+	tagMap map[string]TagSetting, report *Report, visiting map[string]bool,
+) {
+	// 主表的行都有错误时不再读取子表
+	if len(parents) == 0 && len(parentData.RowIndexes) > 0 {
+		return
+	}
+
+	for _, field := range childrenFields(parentType, tagMap) {
+		if p.FailFast && report.HasError() {
+			return
+		}
+		tagSetting := tagMap[field.Name]
+		if visiting[tagSetting.Children] {
+			continue
+		}
+
+		childData, ok := excelData.SheetNameData[tagSetting.Children]
+		if !ok {
+			report.add(tagSetting.Children, nil, 0, NewError(p.fileName, "",
+				fmt.Sprintf("sheetName %s", tagSetting.Children), ErrorSheetName))
+			continue
+		}
+		if !sliceutil.InSlice(tagSetting.PrimaryKey, parentData.FieldKeys) {
+			report.add(parentData.SheetName, parentData.FieldKeys, parentData.HeaderRowIndex,
+				newRowError(p.fileName, parentData.SheetName, parentData.HeaderRowIndex, tagSetting.PrimaryKey,
+					ErrorFieldNotExist))
+			continue
+		}
+
+		p.currentSheetName = childData.SheetName
+		visiting[childData.SheetName] = true
+		p.readChildSheet(excelData, parentData, parents, parentRows, field, tagSetting, childData, report, visiting)
+		delete(visiting, childData.SheetName)
+		p.currentSheetName = parentData.SheetName
+	}
+}
+
+// readChildSheet decode the child sheet of the children field and attach the child rows to the parents
+func (p *Parser) readChildSheet(
+	excelData *Data, parentData *SheetData, parents []reflect.Value, parentRows []int, field structField,
+	tagSetting TagSetting, childData *SheetData, report *Report, visiting map[string]bool,
+) {
+	childType := indirectType(field.Type.Elem())
+	childTagMap, err := p.fieldTagSetting(childType)
+	if err != nil {
+		report.add(childData.SheetName, nil, 0, err)
+		return
+	}
+	fk := tagSetting.ForeignKey
+	if p.checkHeader(childData, childType, childTagMap, report, fk) {
+		return
+	}
+	if !sliceutil.InSlice(fk, childData.FieldKeys) {
+		report.add(childData.SheetName, childData.FieldKeys, childData.HeaderRowIndex,
+			newRowError(p.fileName, childData.SheetName, childData.HeaderRowIndex, fk, ErrorFieldNotExist))
+		return
+	}
+
+	// 主表所有行的键，包括有错误的行，用于检查子表的外键
+	parentKeys := make(map[string]struct{}, len(parentData.RowIndexes))
+	for _, rowIndex := range parentData.RowIndexes {
+		if cell, err := parentData.GetCell(rowIndex, tagSetting.PrimaryKey); err == nil {
+			parentKeys[cell.Value] = struct{}{}
+		}
+	}
+	parentIndex := make(map[string][]reflect.Value, len(parents))
+	for i, parent := range parents {
+		if cell, err := parentData.GetCell(parentRows[i], tagSetting.PrimaryKey); err == nil {
+			parentIndex[cell.Value] = append(parentIndex[cell.Value], parent)
+		}
+	}
+
+	dangling := make(map[int]bool)
+	for _, rowIndex := range childData.RowIndexes {
+		cell, err := childData.GetCell(rowIndex, fk)
+		if err != nil {
+			continue
+		}
+		if _, ok := parentKeys[cell.Value]; ok {
+			continue
+		}
+		dangling[rowIndex] = true
+		err = NewError(p.fileName, childData.SheetName, cell.Coordinates, ErrorForeignKeyNotExist)
+		setErrorField(err, "", fk, cell.Value)
+		report.add(childData.SheetName, childData.FieldKeys, rowIndex, err)
+		if p.FailFast {
+			return
+		}
+	}
+
+	children, childRows := p.decodeRows(childData, childType, childTagMap, report)
+	p.readChildren(excelData, childData, childType, children, childRows, childTagMap, report, visiting)
+	for i, child := range children {
+		if dangling[childRows[i]] {
+			continue
+		}
+		cell, err := childData.GetCell(childRows[i], fk)
+		if err != nil {
+			continue
+		}
+		for _, parent := range parentIndex[cell.Value] {
+			fieldValue, _ := fieldByIndex(parent.Elem(), field.Index, true)
+			if field.Type.Elem().Kind() == reflect.Ptr {
+				fieldValue.Set(reflect.Append(fieldValue, child))
+			} else {
+				fieldValue.Set(reflect.Append(fieldValue, child.Elem()))
+			}
+		}
+	}
+}
+
+// childLink the foreign key column of the child sheet, values are the primary key values of the parents
+// of the child records in order
+type childLink struct {
+	column string
+	values []interface{}
+	// index the column index of the foreign key in the heads, -1 if the column is inserted as the first column
+	index int
+}
+
+// applyHead insert the foreign key column to the heads if the child struct has no such column
+func (l *childLink) applyHead(heads, comments []interface{}) ([]interface{}, []interface{}) {
+	for i, head := range heads {
+		if head == l.column {
+			l.index = i
+			return heads, comments
+		}
+	}
+
+	l.index = -1
+	heads = append([]interface{}{l.column}, heads...)
+	if comments != nil {
+		comments = append([]interface{}{""}, comments...)
+	}
+	return heads, comments
+}
+
+// applyRow set the foreign key value of the i-th child record to the row
+func (l *childLink) applyRow(rowData []interface{}, i int) []interface{} {
+	if l.index >= 0 {
+		rowData[l.index] = l.values[i]
+		return rowData
+	}
+	return append([]interface{}{l.values[i]}, rowData...)
+}
+
+// writeChildren write the children fields of the records to the linked child sheets,
+// the foreign key column of the child sheet is the primary key value of the parent
+func (p *Parser) writeChildren(
+	excelFile *excelize.File, rv reflect.Value, structType reflect.Type, tagMap map[string]TagSetting,
+) (errs error) {
+	sheetName := p.currentSheetName
+	defer func() {
+		p.currentSheetName = sheetName
+	}()
+
+	fields := structFields(structType, tagMap)
+	for _, field := range childrenFields(structType, tagMap) {
+		tagSetting := tagMap[field.Name]
+		var pkField *structField
+		for i := range fields {
+			if ts, ok := tagMap[fields[i].Name]; ok && !ts.Skip && ts.Column == tagSetting.PrimaryKey {
+				pkField = &fields[i]
+				break
+			}
+		}
+		if pkField == nil {
+			return multierror.Append(errs, newRowError(p.fileName, sheetName, 0, tagSetting.PrimaryKey, ErrorFieldNotExist))
+		}
+
+		children := reflect.MakeSlice(field.Type, 0, 0)
+		link := &childLink{column: tagSetting.ForeignKey}
+		for i := 0; i < rv.Len(); i++ {
+			record := reflect.Indirect(rv.Index(i))
+			if !record.IsValid() {
+				continue
+			}
+			childValue, ok := fieldByIndex(record, field.Index, false)
+			if !ok || childValue.Len() == 0 {
+				continue
+			}
+
+			var pk interface{}
+			if pkValue, ok := fieldByIndex(record, pkField.Index, false); ok {
+				v, err := p.cellValue(pkValue, tagMap[pkField.Name])
+				if err != nil {
+					return multierror.Append(errs, err)
+				}
+				pk = v
+			}
+			for j := 0; j < childValue.Len(); j++ {
+				children = reflect.Append(children, childValue.Index(j))
+				link.values = append(link.values, pk)
+			}
+		}
+
+		p.currentSheetName = tagSetting.Children
+		if err := p.writeRecords(excelFile, children, indirectType(field.Type.Elem()), link); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package excelstructure
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+type PurchaseOrder struct {
+	OrderNo  string          `excel:"column:order_no;required"`
+	Customer string          `excel:"column:customer"`
+	Lines    []*PurchaseLine `excel:"children:Lines;fk:order_no"`
+}
+
+type PurchaseLine struct {
+	Sku      string `excel:"column:sku;required"`
+	Quantity int    `excel:"column:quantity;min:1"`
+}
+
+func Test_WriteReadChildren(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "orders.xlsx")
+	orders := []*PurchaseOrder{
+		{OrderNo: "A001", Customer: "booyang", Lines: []*PurchaseLine{{Sku: "apple", Quantity: 2}, {Sku: "pear", Quantity: 1}}},
+		{OrderNo: "A002", Customer: "boo"},
+		{OrderNo: "A003", Customer: "yang", Lines: []*PurchaseLine{{Sku: "peach", Quantity: 3}}},
+	}
+	p := NewParser()
+	require.NoError(t, p.Write(fileName, "Orders", orders))
+
+	f, err := excelize.OpenFile(fileName)
+	require.NoError(t, err)
+	rows, err := f.GetRows("Lines")
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		{"order_no", "sku", "quantity"},
+		{"A001", "apple", "2"},
+		{"A001", "pear", "1"},
+		{"A003", "peach", "3"},
+	}, rows)
+	require.NoError(t, f.Close())
+
+	var newOrders []*PurchaseOrder
+	require.NoError(t, p.ReadWithMultiSheet(fileName, map[string]interface{}{"Orders": &newOrders}))
+	require.Equal(t, orders, newOrders)
+}
+
+func Test_ReadChildrenDanglingForeignKey(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "orders.xlsx")
+	f := excelize.NewFile()
+	require.NoError(t, f.SetSheetName("Sheet1", "Orders"))
+	_, err := f.NewSheet("Lines")
+	require.NoError(t, err)
+	for sheetName, rows := range map[string][][]interface{}{
+		"Orders": {{"order_no", "customer"}, {"A001", "booyang"}},
+		"Lines":  {{"sku", "order_no", "quantity"}, {"apple", "A001", 2}, {"pear", "A009", 1}},
+	} {
+		for i := range rows {
+			cell, _ := excelize.CoordinatesToCellName(1, i+1)
+			require.NoError(t, f.SetSheetRow(sheetName, cell, &rows[i]))
+		}
+	}
+	require.NoError(t, f.SaveAs(fileName))
+	require.NoError(t, f.Close())
+
+	var orders []PurchaseOrder
+	err = NewParser().ReadWithMultiSheet(fileName, map[string]interface{}{"Orders": &orders})
+	require.Error(t, err)
+	errs := ErrorList(err)
+	require.Len(t, errs, 1)
+	require.Equal(t, "Lines", errs[0].SheetName)
+	require.Equal(t, "B3", errs[0].Coordinates)
+	require.Equal(t, ErrorCodeForeignKey, errs[0].Code)
+	require.Equal(t, "A009", errs[0].Value)
+}
+
+type Shipment struct {
+	No       string            `excel:"column:no"`
+	Lines    []*PurchaseLine   `excel:"children:Lines"`
+	Tracking string            `excel:"column:tracking;children:Events;fk:no"`
+	Notes    map[string]string `excel:"children:Notes;fk:no"`
+}
+
+func Test_ChildrenTagInvalid(t *testing.T) {
+	var shipments []Shipment
+	err := NewParser().Read(newSheetFile(t, [][]string{{"no"}, {"S001"}}), &shipments)
+	require.ErrorIs(t, err, ErrorTagInvalid)
+	fields := make([]string, 0, 3)
+	for _, e := range ErrorList(err) {
+		fields = append(fields, e.Field)
+	}
+	require.Equal(t, []string{"Lines", "Tracking", "Notes"}, fields)
+}
+
+type Refund struct {
+	OrderNo string          `excel:"column:order_no"`
+	Lines   []*PurchaseLine `excel:"children:Lines;fk:order_no"`
+}
+
+func Test_WriteChildrenSheetNameRepeat(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "orders.xlsx")
+	orders := []*PurchaseOrder{{OrderNo: "A001", Lines: []*PurchaseLine{{Sku: "apple", Quantity: 2}}}}
+
+	// the child sheet has the same name as a sheet of the input map
+	err := NewParser().WriteWithMultiSheet(fileName, map[string]interface{}{
+		"Orders": orders,
+		"Lines":  []*PurchaseLine{{Sku: "pear", Quantity: 1}},
+	})
+	require.ErrorIs(t, err, ErrorSheetNameRepeat)
+	require.Equal(t, "Lines", ErrorList(err)[0].SheetName)
+
+	// two children fields write to the same sheet
+	err = NewParser().WriteWithMultiSheet(fileName, map[string]interface{}{
+		"Orders":  orders,
+		"Refunds": []*Refund{{OrderNo: "A001", Lines: []*PurchaseLine{{Sku: "apple", Quantity: 1}}}},
+	})
+	require.ErrorIs(t, err, ErrorSheetNameRepeat)
+}
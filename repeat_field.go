@@ -0,0 +1,165 @@
+package excelstructure
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// repeatPlaceholder the placeholder of the column number in the column template of repeat field
+const repeatPlaceholder = "{n}"
+
+// isRepeatColumn whether the column template of repeat tag contains the placeholder
+func isRepeatColumn(column string) bool {
+	return strings.Contains(column, repeatPlaceholder)
+}
+
+// repeatColumnName the column name of the template with the number n, start with 1
+func repeatColumnName(template string, n int) string {
+	return strings.ReplaceAll(template, repeatPlaceholder, strconv.Itoa(n))
+}
+
+// repeatPattern the regexp matching the columns of the template, the submatch is the number
+func repeatPattern(template string) *regexp.Regexp {
+	parts := strings.Split(template, repeatPlaceholder)
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, `([0-9]+)`) + "$")
+}
+
+// matchRepeatColumn the number of the column matching the pattern, false if not matched
+func matchRepeatColumn(pattern *regexp.Regexp, column string) (int, bool) {
+	match := pattern.FindStringSubmatch(column)
+	if match == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(match[1])
+	if err != nil || n < 1 {
+		return 0, false
+	}
+	return n, true
+}
+
+// repeatColumn the column of the sheet matching the repeat template
+type repeatColumn struct {
+	n      int
+	column string
+}
+
+// repeatColumns the columns of fieldKeys matching the pattern of repeat template in the order of number
+func repeatColumns(pattern *regexp.Regexp, fieldKeys []string) []repeatColumn {
+	var columns []repeatColumn
+	for _, fieldKey := range fieldKeys {
+		if n, ok := matchRepeatColumn(pattern, fieldKey); ok {
+			columns = append(columns, repeatColumn{n: n, column: fieldKey})
+		}
+	}
+	sort.SliceStable(columns, func(i, j int) bool {
+		return columns[i].n < columns[j].n
+	})
+	return columns
+}
+
+// setRepeatField gather the non-empty cells of the columns matching the template into the slice field,
+// every element is validated and set like a single field
+func (p *Parser) setRepeatField(
+	ve reflect.Value, field structField, sheetData *SheetData, rowIndex int, tagSetting TagSetting,
+) error {
+	var errs error
+	elemType := field.Type.Elem()
+	slice := reflect.MakeSlice(field.Type, 0, 0)
+	var firstCell *Cell
+	for _, column := range repeatColumns(tagSetting.repeatPattern, sheetData.FieldKeys) {
+		cell, err := sheetData.GetCell(rowIndex, column.column)
+		if err != nil {
+			setErrorField(err, field.Name, column.column, "")
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		if firstCell == nil {
+			firstCell = cell
+		}
+		if cell.Value == "" {
+			continue
+		}
+
+		if validateErrs := p.validateCell(cell, cell.Value, indirectType(elemType), tagSetting); len(validateErrs) > 0 {
+			for _, err := range validateErrs {
+				setErrorField(err, field.Name, column.column, cell.Value)
+			}
+			errs = multierror.Append(errs, validateErrs...)
+			continue
+		}
+
+		elem := reflect.New(elemType).Elem()
+		if err = p.setField(elem, indirectType(elemType), cell, tagSetting); err != nil {
+			setErrorField(err, field.Name, column.column, cell.Value)
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		slice = reflect.Append(slice, elem)
+	}
+	if errs != nil {
+		return errs
+	}
+
+	if slice.Len() == 0 {
+		if !tagSetting.Required && !p.IsCheckEmpty {
+			return nil
+		}
+		err := newRowError(p.fileName, p.currentSheetName, rowIndex, tagSetting.Column, ErrorFieldValueEmpty)
+		if firstCell != nil {
+			err = NewError(p.fileName, p.currentSheetName, firstCell.Coordinates, ErrorFieldValueEmpty)
+		}
+		setErrorField(err, field.Name, tagSetting.Column, "")
+		return err
+	}
+
+	fieldValue, _ := fieldByIndex(ve, field.Index, true)
+	fieldValue.Set(slice)
+	return nil
+}
+
+// repeatCounts the column count of the repeat fields, the max length of the slices of all records,
+// key is the field path
+func repeatCounts(rv reflect.Value, tagMap map[string]TagSetting) map[string]int {
+	counts := make(map[string]int)
+	var fields []structField
+	for i := 0; i < rv.Len(); i++ {
+		elemValue := reflect.Indirect(rv.Index(i))
+		if !elemValue.IsValid() {
+			continue
+		}
+		if fields == nil {
+			fields = structFields(elemValue.Type(), tagMap)
+		}
+		for _, field := range fields {
+			if !tagMap[field.Name].Repeat {
+				continue
+			}
+			fieldValue, ok := fieldByIndex(elemValue, field.Index, false)
+			if ok && fieldValue.Len() > counts[field.Name] {
+				counts[field.Name] = fieldValue.Len()
+			}
+		}
+	}
+	return counts
+}
+
+// repeatCellValues the cell values of the slice elements spread across count columns, nil if out of the slice
+func (p *Parser) repeatCellValues(fieldValue reflect.Value, tagSetting TagSetting, count int) ([]interface{}, error) {
+	values := make([]interface{}, count)
+	for i := 0; i < count && i < fieldValue.Len(); i++ {
+		v, err := p.cellValue(fieldValue.Index(i), tagSetting)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
@@ -0,0 +1,92 @@
+package excelstructure
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type Customer struct {
+	Name   string            `excel:"column:name"`
+	Phones []string          `excel:"column:Phone {n}|电话{n};repeat"`
+	Scores []int             `excel:"column:score_{n};repeat;max:100"`
+	Attrs  map[string]string `excel:"extra"`
+}
+
+func Test_WriteReadRepeatField(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "customer.xlsx")
+	customers := []Customer{
+		{Name: "booyang", Phones: []string{"13800000000", "13900000000", "13700000000"}, Scores: []int{90}},
+		{Name: "boo", Phones: []string{"13600000000"}},
+	}
+	p := NewParser()
+	p.StrictHeader = true
+	require.NoError(t, p.Write(fileName, "", customers))
+
+	data, err := NewParser().Parse(fileName)
+	require.NoError(t, err)
+	require.Equal(t, []string{"name", "Phone 1", "Phone 2", "Phone 3", "score_1"},
+		data.SheetNameData["Customers"].FieldKeys)
+
+	var newCustomers []Customer
+	require.NoError(t, p.Read(fileName, &newCustomers))
+	require.Equal(t, customers, newCustomers)
+}
+
+func Test_ReadRepeatField(t *testing.T) {
+	fileName := newSheetFile(t, [][]string{
+		{"name", "电话2", "电话1", "score_1", "score_2", "level"},
+		{"booyang", "13900000000", "13800000000", "90", "101", "vip"},
+		{"boo", "", "13600000000", "abc"},
+	})
+
+	var customers []Customer
+	err := NewParser().Read(fileName, &customers)
+	require.Error(t, err)
+	require.Empty(t, customers)
+	errs := ErrorList(err)
+	require.Len(t, errs, 2)
+	require.Equal(t, "E2", errs[0].Coordinates)
+	require.Equal(t, ErrorCodeMax, errs[0].Code)
+	require.Equal(t, "score_2", errs[0].Column)
+	require.Equal(t, "D3", errs[1].Coordinates)
+	require.Equal(t, ErrorCodeTypeMismatch, errs[1].Code)
+
+	fileName = newSheetFile(t, [][]string{
+		{"name", "电话2", "电话1", "level"},
+		{"booyang", "13900000000", "13800000000", "vip"},
+		{"boo", "", "13600000000"},
+	})
+	require.NoError(t, NewParser().Read(fileName, &customers))
+	require.Equal(t, []Customer{
+		{Name: "booyang", Phones: []string{"13800000000", "13900000000"}, Attrs: map[string]string{"level": "vip"}},
+		{Name: "boo", Phones: []string{"13600000000"}},
+	}, customers)
+}
+
+type Station struct {
+	Name    string   `excel:"column:name"`
+	Channel string   `excel:"column:channel {n};repeat"`
+	Tags    []string `excel:"column:tag;repeat"`
+	Phones  []string `excel:"column:phone {n};repeat"`
+}
+
+func Test_RepeatTagInvalid(t *testing.T) {
+	fileName := newSheetFile(t, [][]string{{"name", "phone 1"}, {"booyang", "13800000000"}})
+
+	var stations []Station
+	err := NewParser().Read(fileName, &stations)
+	require.ErrorIs(t, err, ErrorTagInvalid)
+	errs := ErrorList(err)
+	require.Len(t, errs, 2)
+	require.Equal(t, "Channel", errs[0].Field)
+	require.Equal(t, "Tags", errs[1].Field)
+
+	// 映射的列名同样需要包含{n}
+	var customers []Customer
+	p := NewParser().WithColumnMapping(ColumnMap{"Phones": "phone"})
+	err = p.Read(fileName, &customers)
+	require.ErrorIs(t, err, ErrorTagInvalid)
+	require.Equal(t, "Phones", ErrorList(err)[0].Field)
+}
@@ -0,0 +1,281 @@
+package excelstructure
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// ErrorCode 错误码，用于按错误类型统计和展示
+type ErrorCode string
+
+const (
+	// ErrorCodeInvalid 其他错误，如自定义序列化、转换器返回的错误
+	ErrorCodeInvalid ErrorCode = "invalid"
+	// ErrorCodeSheetNotExist sheet不存在
+	ErrorCodeSheetNotExist ErrorCode = "sheet_not_exist"
+	// ErrorCodeOutputType 输出类型错误
+	ErrorCodeOutputType ErrorCode = "output_type"
+	// ErrorCodeColumnMissing 表头缺少结构体字段对应的列
+	ErrorCodeColumnMissing ErrorCode = "column_missing"
+	// ErrorCodeColumnRepeat 表头列重复
+	ErrorCodeColumnRepeat ErrorCode = "column_repeat"
+	// ErrorCodeColumnUnknown 表头列没有对应的结构体字段，仅Parser.StrictHeader为true时报告
+	ErrorCodeColumnUnknown ErrorCode = "column_unknown"
+	// ErrorCodeRequired 值为空
+	ErrorCodeRequired ErrorCode = "required"
+	// ErrorCodeTypeMismatch 值与字段类型不匹配
+	ErrorCodeTypeMismatch ErrorCode = "type_mismatch"
+	// ErrorCodeTypeNotSupport 字段类型不支持
+	ErrorCodeTypeNotSupport ErrorCode = "type_not_support"
+	// ErrorCodeTagInvalid 结构体标签的设置无效，如col:C1
+	ErrorCodeTagInvalid ErrorCode = "tag_invalid"
+	// ErrorCodeSerializerNotExist 序列化器不存在
+	ErrorCodeSerializerNotExist ErrorCode = "serializer_not_exist"
+	// ErrorCodeMin 校验规则min
+	ErrorCodeMin ErrorCode = "min"
+	// ErrorCodeMax 校验规则max
+	ErrorCodeMax ErrorCode = "max"
+	// ErrorCodeLen 校验规则len
+	ErrorCodeLen ErrorCode = "len"
+	// ErrorCodeRegex 校验规则regex
+	ErrorCodeRegex ErrorCode = "regex"
+	// ErrorCodeOneOf 校验规则oneof
+	ErrorCodeOneOf ErrorCode = "oneof"
+	// ErrorCodeEmail 校验规则email
+	ErrorCodeEmail ErrorCode = "email"
+	// ErrorCodeURL 校验规则url
+	ErrorCodeURL ErrorCode = "url"
+	// ErrorCodeForeignKey 子表的外键在主表中不存在
+	ErrorCodeForeignKey ErrorCode = "foreign_key"
+)
+
+var errorCodes = []struct {
+	err  error
+	code ErrorCode
+}{
+	{ErrorSheetName, ErrorCodeSheetNotExist},
+	{ErrorInOutputType, ErrorCodeOutputType},
+	{ErrorSliceElemType, ErrorCodeOutputType},
+	{ErrorTypePointer, ErrorCodeOutputType},
+	{ErrorFieldNotExist, ErrorCodeColumnMissing},
+	{ErrorFieldRepeat, ErrorCodeColumnRepeat},
+	{ErrorColumnUnknown, ErrorCodeColumnUnknown},
+	{ErrorFieldValueEmpty, ErrorCodeRequired},
+	{ErrorFieldNotMatch, ErrorCodeTypeMismatch},
+	{ErrorFieldTypeNotSupport, ErrorCodeTypeNotSupport},
+	{ErrorTagInvalid, ErrorCodeTagInvalid},
+	{ErrorSerializerNotExist, ErrorCodeSerializerNotExist},
+	{ErrorValidateMin, ErrorCodeMin},
+	{ErrorValidateMax, ErrorCodeMax},
+	{ErrorValidateLen, ErrorCodeLen},
+	{ErrorValidateRegex, ErrorCodeRegex},
+	{ErrorValidateOneOf, ErrorCodeOneOf},
+	{ErrorValidateEmail, ErrorCodeEmail},
+	{ErrorValidateURL, ErrorCodeURL},
+	{ErrorForeignKeyNotExist, ErrorCodeForeignKey},
+}
+
+// GetErrorCode 错误对应的错误码，未知错误为ErrorCodeInvalid
+func GetErrorCode(err error) ErrorCode {
+	var e *Error
+	if errors.As(err, &e) && e.Code != "" {
+		return e.Code
+	}
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return ErrorCodeInvalid
+}
+
+// ReportItem 报告中的单个错误
+type ReportItem struct {
+	SheetName string
+	// RowIndex excel行号，从1开始，0为sheet级别的错误
+	RowIndex int
+	// Column 表头列名，为空则为行或者sheet级别的错误
+	Column string
+	// Field 结构体字段名
+	Field string
+	// Coordinates 单元格坐标，如A1，为空则不是单元格的错误
+	Coordinates string
+	Code        ErrorCode
+	Err         error
+}
+
+// Report 读取到结构体的错误报告，读取有错误时作为error返回
+//
+//	err := p.Read(fileName, &output)
+//	var report *Report
+//	if errors.As(err, &report) {
+//		for _, item := range report.Items {...}
+//	}
+type Report struct {
+	FileName string
+	// Items 所有错误，按sheet和行的顺序
+	Items []*ReportItem
+
+	rows    map[string]map[int][]*ReportItem
+	columns map[string]map[string][]*ReportItem
+	cells   map[string]map[string][]*ReportItem
+	// rowIndexes 有错误的行，key为sheetName
+	rowIndexes map[string][]int
+}
+
+func newReport(fileName string) *Report {
+	return &Report{
+		FileName:   fileName,
+		rows:       make(map[string]map[int][]*ReportItem),
+		columns:    make(map[string]map[string][]*ReportItem),
+		cells:      make(map[string]map[string][]*ReportItem),
+		rowIndexes: make(map[string][]int),
+	}
+}
+
+// Error error
+func (r *Report) Error() string {
+	lines := make([]string, 0, len(r.Items))
+	for _, item := range r.Items {
+		lines = append(lines, fmt.Sprintf("\t* %s", item.Err))
+	}
+	return fmt.Sprintf("%d errors occurred:\n%s\n\n", len(r.Items), strings.Join(lines, "\n"))
+}
+
+// Len 错误数量
+func (r *Report) Len() int {
+	return len(r.Items)
+}
+
+// HasError 是否有错误
+func (r *Report) HasError() bool {
+	return len(r.Items) > 0
+}
+
+// RowIndexes sheet中有错误的行号，按行顺序
+func (r *Report) RowIndexes(sheetName string) []int {
+	return r.rowIndexes[sheetName]
+}
+
+// RowErrors sheet中某一行的错误
+func (r *Report) RowErrors(sheetName string, rowIndex int) []*ReportItem {
+	return r.rows[sheetName][rowIndex]
+}
+
+// ColumnErrors sheet中某一列的错误，column为表头列名
+func (r *Report) ColumnErrors(sheetName, column string) []*ReportItem {
+	return r.columns[sheetName][column]
+}
+
+// CellErrors sheet中某一单元格的错误，coordinates如A1或者$A$1
+func (r *Report) CellErrors(sheetName, coordinates string) []*ReportItem {
+	return r.cells[sheetName][strings.ReplaceAll(coordinates, "$", "")]
+}
+
+// CodeCounts 按错误码统计错误数量
+func (r *Report) CodeCounts() map[ErrorCode]int {
+	counts := make(map[ErrorCode]int)
+	for _, item := range r.Items {
+		counts[item.Code]++
+	}
+	return counts
+}
+
+// Is errors.Is(report, target) is true if any error of the report matches target
+func (r *Report) Is(target error) bool {
+	for _, item := range r.Items {
+		if errors.Is(item.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As errors.As(report, target) finds the first error of the report that matches target
+func (r *Report) As(target interface{}) bool {
+	for _, item := range r.Items {
+		if errors.As(item.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// errorOrNil the report as error, nil if no error
+func (r *Report) errorOrNil() error {
+	if r.HasError() {
+		return r
+	}
+	return nil
+}
+
+// addItem add item and index it by row, column and cell
+func (r *Report) addItem(item *ReportItem) {
+	r.Items = append(r.Items, item)
+
+	if item.RowIndex > 0 {
+		if r.rows[item.SheetName] == nil {
+			r.rows[item.SheetName] = make(map[int][]*ReportItem)
+		}
+		if _, ok := r.rows[item.SheetName][item.RowIndex]; !ok {
+			r.rowIndexes[item.SheetName] = append(r.rowIndexes[item.SheetName], item.RowIndex)
+		}
+		r.rows[item.SheetName][item.RowIndex] = append(r.rows[item.SheetName][item.RowIndex], item)
+	}
+	if item.Column != "" {
+		if r.columns[item.SheetName] == nil {
+			r.columns[item.SheetName] = make(map[string][]*ReportItem)
+		}
+		r.columns[item.SheetName][item.Column] = append(r.columns[item.SheetName][item.Column], item)
+	}
+	if item.Coordinates != "" {
+		if r.cells[item.SheetName] == nil {
+			r.cells[item.SheetName] = make(map[string][]*ReportItem)
+		}
+		r.cells[item.SheetName][item.Coordinates] = append(r.cells[item.SheetName][item.Coordinates], item)
+	}
+}
+
+// add add the errors of sheet, the errors of multierror are added one by one,
+// the column and coordinates are resolved from the cell coordinates of the error
+func (r *Report) add(sheetName string, fieldKeys []string, rowIndex int, err error) {
+	if err == nil {
+		return
+	}
+
+	errs := []error{err}
+	if merr, ok := err.(*multierror.Error); ok {
+		errs = merr.Errors
+	}
+	for _, e := range errs {
+		if report, ok := e.(*Report); ok {
+			for _, item := range report.Items {
+				r.addItem(item)
+			}
+			continue
+		}
+
+		item := &ReportItem{
+			SheetName: sheetName,
+			RowIndex:  rowIndex,
+			Code:      GetErrorCode(e),
+			Err:       e,
+		}
+		if ee, ok := e.(*Error); ok {
+			if ee.ColIndex > 0 {
+				item.Coordinates = strings.ReplaceAll(ee.Coordinates, "$", "")
+				if ee.Column == "" && ee.ColIndex <= len(fieldKeys) {
+					ee.Column = fieldKeys[ee.ColIndex-1]
+				}
+			}
+			item.Field, item.Column = ee.Field, ee.Column
+			if ee.RowIndex > 0 {
+				item.RowIndex = ee.RowIndex
+			}
+		}
+		r.addItem(item)
+	}
+}
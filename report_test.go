@@ -0,0 +1,71 @@
+package excelstructure
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Report(t *testing.T) {
+	fileName := newMemberFile(t)
+	p := NewParser()
+
+	var members []Member
+	err := p.Read(fileName, &members)
+	var report *Report
+	require.True(t, errors.As(err, &report))
+	require.Equal(t, 12, report.Len())
+	require.Equal(t, []int{3, 4}, report.RowIndexes("Sheet1"))
+	require.Equal(t, 8, len(report.RowErrors("Sheet1", 3)))
+	require.Equal(t, 0, len(report.RowErrors("Sheet1", 2)))
+
+	phoneErrors := report.ColumnErrors("Sheet1", "phone")
+	require.Equal(t, 3, len(phoneErrors))
+	require.Equal(t, ErrorCodeLen, phoneErrors[0].Code)
+	require.Equal(t, ErrorCodeRegex, phoneErrors[1].Code)
+	require.Equal(t, ErrorCodeRegex, phoneErrors[2].Code)
+	require.Equal(t, phoneErrors[:2], report.CellErrors("Sheet1", "$C$3"))
+
+	counts := report.CodeCounts()
+	require.Equal(t, 1, counts[ErrorCodeRequired])
+	require.Equal(t, 3, counts[ErrorCodeMin])
+	require.Equal(t, 1, counts[ErrorCodeTypeMismatch])
+
+	// the parser is reusable, the same errors are reported again
+	err = p.Read(fileName, &members)
+	require.True(t, errors.As(err, &report))
+	require.Equal(t, 12, report.Len())
+
+	p.FailFast = true
+	err = p.Read(fileName, &members)
+	require.True(t, errors.As(err, &report))
+	require.Equal(t, 1, report.Len())
+	require.Equal(t, "A3", report.Items[0].Coordinates)
+	require.Equal(t, "name", report.Items[0].Column)
+	require.Equal(t, ErrorCodeRequired, report.Items[0].Code)
+}
+
+func Test_ReportColumnMissing(t *testing.T) {
+	fileName := newMemberFile(t)
+
+	type Account struct {
+		Name    string `excel:"column:name"`
+		Account string `excel:"column:account"`
+		Bank    string `excel:"column:bank"`
+	}
+	var accounts []Account
+	err := NewParser().ReadWithMultiSheet(fileName, map[string]interface{}{
+		"Sheet1": &accounts,
+		"Sheet2": &accounts,
+	})
+	var report *Report
+	require.True(t, errors.As(err, &report))
+	require.Equal(t, 3, report.Len())
+	// missing column is reported once instead of every row
+	require.Equal(t, []ErrorCode{ErrorCodeColumnMissing, ErrorCodeColumnMissing, ErrorCodeSheetNotExist},
+		[]ErrorCode{report.Items[0].Code, report.Items[1].Code, report.Items[2].Code})
+	require.Equal(t, 1, len(report.ColumnErrors("Sheet1", "account")))
+	require.Equal(t, 1, len(report.ColumnErrors("Sheet1", "bank")))
+	require.Equal(t, 2, len(report.RowErrors("Sheet1", 1)))
+}
@@ -0,0 +1,134 @@
+package excelstructure
+
+import "reflect"
+
+// SheetSetting 单个sheet的表头设置，零值字段使用Parser的设置
+type SheetSetting struct {
+	// HeaderRowIndex 表头行索引，从1开始
+	HeaderRowIndex int
+	// DataIndexOffset 数据索引偏移量，从表头行开始计算，表头占一行则为1，表头下有注释行则为2
+	DataIndexOffset int
+	// HeaderScanRows 大于0时，读取到结构体时在前N行中查找与结构体列名匹配最多的行作为表头
+	HeaderScanRows int
+	// NoHeader sheet没有表头，以列字母作为表头，数据从第DataIndexOffset行开始
+	NoHeader bool
+}
+
+// sheetSetting merge parser setting, the layout recorded in workbook and the sheet setting in order
+func (p *Parser) sheetSetting(sheetName string, layouts map[string]sheetLayout) SheetSetting {
+	setting := SheetSetting{
+		HeaderRowIndex:  p.HeaderRowIndex,
+		DataIndexOffset: p.DataIndexOffset,
+		HeaderScanRows:  p.HeaderScanRows,
+		NoHeader:        p.NoHeader,
+	}
+
+	if layout, ok := layouts[sheetName]; ok && !p.IgnoreLayout {
+		setting.HeaderRowIndex = layout.HeaderRowIndex
+		setting.DataIndexOffset = layout.DataRowIndex - layout.HeaderRowIndex
+		setting.HeaderScanRows = 0
+		setting.NoHeader = false
+	}
+
+	if s, ok := p.SheetSettings[sheetName]; ok {
+		if s.HeaderRowIndex > 0 {
+			setting.HeaderRowIndex = s.HeaderRowIndex
+		}
+		if s.DataIndexOffset > 0 {
+			setting.DataIndexOffset = s.DataIndexOffset
+		}
+		if s.HeaderScanRows > 0 {
+			setting.HeaderScanRows = s.HeaderScanRows
+		}
+		if s.NoHeader {
+			setting.NoHeader = true
+		}
+	}
+
+	if setting.HeaderRowIndex < 1 {
+		setting.HeaderRowIndex = 1
+	}
+	if setting.DataIndexOffset < 1 {
+		setting.DataIndexOffset = 1
+	}
+	return setting
+}
+
+// sheetSchema the struct to read the sheet to
+type sheetSchema struct {
+	structType reflect.Type
+	tagMap     map[string]TagSetting
+	// vertical the sheet is read to a struct pointer, column A is the keys and column B is the values
+	vertical bool
+}
+
+// setSchemas record the struct tag settings of the sheets to read, key is sheetName, empty is the first sheet.
+// the schemas are used to resolve the sheet header when parsing, the structs with invalid tags are reported
+// when reading to struct
+func (p *Parser) setSchemas(sheetDataMap map[string]interface{}) {
+	p.schemas = make(map[string]sheetSchema, len(sheetDataMap))
+	for sheetName, output := range sheetDataMap {
+		if structType, ok := getOutputStructType(output); ok {
+			if tagMap, err := p.fieldTagSetting(structType); err == nil {
+				p.schemas[sheetName] = sheetSchema{structType: structType, tagMap: tagMap, vertical: true}
+			}
+			continue
+		}
+		elemType, err := getOutputElemType(output)
+		if err != nil {
+			continue
+		}
+		if tagMap, err := p.fieldTagSetting(elemType); err == nil {
+			p.schemas[sheetName] = sheetSchema{structType: elemType, tagMap: tagMap}
+		}
+	}
+	// 子表按children字段的结构体解析表头，不覆盖直接读取的sheet
+	for _, output := range sheetDataMap {
+		elemType, err := getOutputElemType(output)
+		if err != nil {
+			continue
+		}
+		if tagMap, err := p.fieldTagSetting(elemType); err == nil {
+			p.addChildSchemas(elemType, tagMap)
+		}
+	}
+}
+
+// schema the struct schema of the sheet, false if the sheet is not read to struct
+func (p *Parser) schema(sheetName string, isFirstSheet bool) (sheetSchema, bool) {
+	schema, ok := p.schemas[sheetName]
+	if !ok && isFirstSheet {
+		schema, ok = p.schemas[""]
+	}
+	return schema, ok
+}
+
+// columnResolver the column resolver of the sheet, nil if the sheet is not read to struct
+func (p *Parser) columnResolver(sheetName string, isFirstSheet bool) *columnResolver {
+	schema, ok := p.schema(sheetName, isFirstSheet)
+	if !ok {
+		return nil
+	}
+	return newColumnResolver(schema.structType, schema.tagMap, p.NormalizeHeader)
+}
+
+// detectHeaderRow find the row matching the most columns in the first scanRows rows, return 0 if no row matches
+func detectHeaderRow(rows [][]string, scanRows int, resolver *columnResolver) int {
+	if resolver == nil {
+		return 0
+	}
+
+	headerRowIndex, maxMatch := 0, 0
+	for i := 0; i < scanRows && i < len(rows); i++ {
+		match := 0
+		for _, v := range rows[i] {
+			if _, ok := resolver.resolve(v); ok {
+				match++
+			}
+		}
+		if match > maxMatch {
+			headerRowIndex, maxMatch = i+1, match
+		}
+	}
+	return headerRowIndex
+}
@@ -0,0 +1,259 @@
+package excelstructure
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// RowIterator 流式逐行读取sheet，每次只解析当前行，内存占用与sheet大小无关
+//
+//	it, err := p.OpenRows(fileName, "")
+//	defer it.Close()
+//	for it.Next() {
+//		var info Info
+//		if err := it.Scan(&info); err != nil {...}
+//	}
+//	err = it.Err()
+type RowIterator struct {
+	parser    *Parser
+	excelFile *excelize.File
+	rows      *excelize.Rows
+	// sheetData 仅保存表头和当前行
+	sheetData *SheetData
+	rowIndex  int
+	tagMaps   map[reflect.Type]map[string]TagSetting
+	noHeader  bool
+	err       error
+	// header 表头的原始文本，rawRow 当前行的原始数据，Scan时按结构体的列名和别名重新解析
+	header []string
+	rawRow []string
+	// resolvedType 表头已按该结构体解析
+	resolvedType reflect.Type
+}
+
+// OpenRows 打开sheet的流式行迭代器，sheetName为空则为第一个sheet，使用完成后需要Close
+// 表头使用HeaderRowIndex和SheetSettings设置，不支持HeaderScanRows自动查找表头，
+// Scan时表头按结构体的列名、别名、NormalizeHeader和col、index标签解析，与Read一致
+func (p *Parser) OpenRows(fileName, sheetName string) (*RowIterator, error) {
+	excelFile, err := excelize.OpenFile(fileName)
+	if err != nil {
+		return nil, NewError(fileName, sheetName, "", err)
+	}
+
+	return p.openRows(fileName, sheetName, excelFile)
+}
+
+// OpenRowsFromReader 从reader打开sheet的流式行迭代器，fileName为逻辑文件名，仅用于错误信息
+func (p *Parser) OpenRowsFromReader(r io.Reader, fileName, sheetName string) (*RowIterator, error) {
+	excelFile, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, NewError(fileName, sheetName, "", err)
+	}
+
+	return p.openRows(fileName, sheetName, excelFile)
+}
+
+func (p *Parser) openRows(fileName, sheetName string, excelFile *excelize.File) (*RowIterator, error) {
+	p.fileName = fileName
+	if sheetName == "" {
+		sheetList := excelFile.GetSheetList()
+		if len(sheetList) == 0 {
+			_ = excelFile.Close()
+			return nil, NewError(fileName, "", "", ErrorNoSheet)
+		}
+		sheetName = sheetList[0]
+	}
+	p.currentSheetName = sheetName
+
+	if index, err := excelFile.GetSheetIndex(sheetName); err != nil || index == -1 {
+		_ = excelFile.Close()
+		return nil, NewError(fileName, sheetName, fmt.Sprintf("sheetName %s", sheetName), ErrorSheetName)
+	}
+	rows, err := excelFile.Rows(sheetName)
+	if err != nil {
+		_ = excelFile.Close()
+		return nil, NewError(fileName, sheetName, "", err)
+	}
+
+	setting := p.sheetSetting(sheetName, readLayouts(excelFile))
+	it := &RowIterator{
+		parser:    p,
+		excelFile: excelFile,
+		rows:      rows,
+		sheetData: &SheetData{
+			SheetName:       sheetName,
+			FileName:        fileName,
+			HeaderRowIndex:  setting.HeaderRowIndex,
+			DataIndexOffset: setting.DataIndexOffset + setting.HeaderRowIndex - 1,
+		},
+		tagMaps: make(map[reflect.Type]map[string]TagSetting),
+	}
+	if setting.NoHeader {
+		// 没有表头时读取每行时以列字母补齐表头
+		it.noHeader = true
+		it.sheetData.HeaderRowIndex = 0
+		it.sheetData.DataIndexOffset = setting.DataIndexOffset - 1
+		return it, nil
+	}
+
+	// 读取到表头行
+	for it.rowIndex < setting.HeaderRowIndex {
+		if !rows.Next() {
+			break
+		}
+		it.rowIndex++
+		if it.rowIndex != setting.HeaderRowIndex {
+			continue
+		}
+		it.header, err = rows.Columns()
+		if err != nil {
+			_ = it.Close()
+			return nil, newRowError(fileName, sheetName, it.rowIndex, "", err)
+		}
+		it.sheetData.FieldKeys = it.header
+	}
+
+	if !p.AllowFieldRepeat && findRepeatField(it.sheetData.FieldKeys) != "" {
+		_ = it.Close()
+		return nil, newRowError(fileName, sheetName, setting.HeaderRowIndex, "", ErrorFieldRepeat)
+	}
+
+	return it, nil
+}
+
+// Next 移动到下一个数据行，空行会被跳过，没有更多数据或出错时返回false
+func (it *RowIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	for it.rows.Next() {
+		it.rowIndex++
+		if it.rowIndex <= it.sheetData.DataIndexOffset {
+			continue
+		}
+
+		rawRow, err := it.rows.Columns()
+		if err != nil {
+			it.err = newRowError(it.sheetData.FileName, it.sheetData.SheetName, it.rowIndex, "", err)
+			return false
+		}
+		if len(rawRow) == 0 {
+			continue
+		}
+
+		if it.noHeader && len(rawRow) > len(it.sheetData.FieldKeys) {
+			it.sheetData.FieldKeys = columnLetters(len(rawRow))
+		}
+		it.rawRow = rawRow
+		it.setRow()
+		it.sheetData.RowIndexes = []int{it.rowIndex}
+		it.sheetData.RowTotal = it.rowIndex
+		it.sheetData.DataTotal = it.rowIndex - it.sheetData.DataIndexOffset
+		return true
+	}
+
+	if err := it.rows.Error(); err != nil {
+		it.err = NewError(it.sheetData.FileName, it.sheetData.SheetName, "", err)
+	}
+	it.sheetData.Rows = nil
+	it.sheetData.RowIndexes = nil
+	return false
+}
+
+// setRow parse the raw row of the current row by the field keys
+func (it *RowIterator) setRow() {
+	rowData := make(map[int]map[string]*Cell, 1)
+	it.parser.getRow(it.rowIndex, it.rawRow, it.sheetData.FieldKeys, rowData)
+	it.sheetData.Rows = rowData
+}
+
+// resolveHeader resolve the header texts to the struct columns by the same resolver as Read,
+// the current row is parsed again by the resolved field keys
+func (it *RowIterator) resolveHeader(structType reflect.Type, tagMap map[string]TagSetting) error {
+	p := it.parser
+	it.resolvedType = structType
+	if it.noHeader {
+		return nil
+	}
+	fieldKeys := newColumnResolver(structType, tagMap, p.NormalizeHeader).resolveHeader(it.header)
+	if !p.AllowFieldRepeat && findRepeatField(fieldKeys) != "" {
+		return newRowError(it.sheetData.FileName, it.sheetData.SheetName, it.sheetData.HeaderRowIndex, "",
+			ErrorFieldRepeat)
+	}
+	it.sheetData.FieldKeys = fieldKeys
+	if it.rawRow != nil {
+		it.setRow()
+	}
+	return nil
+}
+
+// RowIndex 当前行的excel行号，从1开始
+func (it *RowIterator) RowIndex() int {
+	return it.rowIndex
+}
+
+// FieldKeys 表头字段，Scan后为按结构体解析后的列名
+func (it *RowIterator) FieldKeys() []string {
+	return it.sheetData.FieldKeys
+}
+
+// Row 当前行数据 map[keyField]*Cell
+func (it *RowIterator) Row() map[string]*Cell {
+	return it.sheetData.Rows[it.rowIndex]
+}
+
+// Scan 解析当前行到output，output必须是struct指针，或者struct指针的指针(为nil时自动创建)
+func (it *RowIterator) Scan(output interface{}) error {
+	p := it.parser
+	rv := reflect.ValueOf(output)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return NewError(p.fileName, p.currentSheetName, "", ErrorTypePointer)
+	}
+	if rv.Elem().Kind() == reflect.Ptr {
+		if rv.Elem().IsNil() {
+			rv.Elem().Set(reflect.New(rv.Elem().Type().Elem()))
+		}
+		rv = rv.Elem()
+	}
+	if rv.Elem().Kind() != reflect.Struct {
+		return NewError(p.fileName, p.currentSheetName, "", ErrorInOutputType)
+	}
+
+	structType := rv.Elem().Type()
+	tagMap, ok := it.tagMaps[structType]
+	if !ok {
+		var err error
+		if tagMap, err = p.fieldTagSetting(structType); err != nil {
+			return err
+		}
+		it.tagMaps[structType] = tagMap
+	}
+	if it.resolvedType != structType {
+		if err := it.resolveHeader(structType, tagMap); err != nil {
+			return err
+		}
+	}
+
+	return p.parseRowToStruct(it.rowIndex, it.sheetData, rv, tagMap)
+}
+
+// Err 迭代过程中的错误
+func (it *RowIterator) Err() error {
+	return it.err
+}
+
+// Close 关闭迭代器和excel文件
+func (it *RowIterator) Close() error {
+	err := it.rows.Close()
+	if err1 := it.excelFile.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		return NewError(it.sheetData.FileName, it.sheetData.SheetName, "", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package excelstructure
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RowIterator(t *testing.T) {
+	p := NewParser()
+	it, err := p.OpenRows("./test_excel_file/test.xlsx", "Sheet2")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, it.Close())
+	}()
+
+	require.Equal(t, []string{"user_name", "phone", "age", "man"}, it.FieldKeys())
+	names := make([]string, 0)
+	rowIndexes := make([]int, 0)
+	for it.Next() {
+		info := &BaseInfo{}
+		assert.NoError(t, it.Scan(info))
+		names = append(names, info.Name)
+		rowIndexes = append(rowIndexes, it.RowIndex())
+	}
+	assert.NoError(t, it.Err())
+	require.Equal(t, []string{"booyang1", "bob1", "tom1", "sandy1"}, names)
+	require.Equal(t, []int{2, 3, 4, 5}, rowIndexes)
+}
+
+func Test_OpenRowsSheetNotExist(t *testing.T) {
+	_, err := NewParser().OpenRows("./test_excel_file/test.xlsx", "NotExist")
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrorSheetName))
+}
+
+func Test_ReadEach(t *testing.T) {
+	p := NewParser()
+	p.IsCheckEmpty = true
+	infos := make([]*BaseInfo, 0)
+	err := ReadEach(p, "./test_excel_file/test_check_empty.xlsx", "", func(rowIndex int, info *BaseInfo) error {
+		infos = append(infos, info)
+		return nil
+	})
+	// one row is empty, the other rows are still read
+	assert.Error(t, err)
+	require.Equal(t, 3, len(infos))
+	require.Equal(t, "booyang", infos[0].Name)
+}
+
+func Test_ReadEachColumnAlias(t *testing.T) {
+	fileName := newSheetFile(t, [][]string{
+		{"User Name", "年龄"},
+		{"booyang", "18"},
+		{"boo", "20"},
+	})
+	var partners []Partner
+	err := ReadEach(NewParser(), fileName, "", func(rowIndex int, partner Partner) error {
+		partners = append(partners, partner)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Partner{{UserName: "booyang", Age: 18}, {UserName: "boo", Age: 20}}, partners)
+
+	// 归一化后匹配列名
+	fileName = newSheetFile(t, [][]string{
+		{" USER_NAME ", "Age"},
+		{"booyang", "18"},
+	})
+	p := NewParser()
+	p.NormalizeHeader = true
+	it, err := p.OpenRows(fileName, "")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, it.Close())
+	}()
+	require.True(t, it.Next())
+	var partner Partner
+	require.NoError(t, it.Scan(&partner))
+	require.Equal(t, Partner{UserName: "booyang", Age: 18}, partner)
+	require.Equal(t, []string{"user_name", "age"}, it.FieldKeys())
+}
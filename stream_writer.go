@@ -0,0 +1,191 @@
+package excelstructure
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/xuri/excelize/v2"
+)
+
+// StreamWriter 流式写入单个sheet，逐条写入记录，内存占用与记录数量无关，适用于大量数据导出
+// 表头在写入记录前生成，extra和repeat字段的列由全部记录决定，children字段需要写入子表，
+// 结构体包含这些字段时返回ErrorFieldTypeNotSupport
+//
+//	w, err := p.NewStreamWriter(fileName, "Infos", &Info{})
+//	for _, info := range infos {
+//		if err := w.Add(info); err != nil {...}
+//	}
+//	err = w.Close()
+type StreamWriter struct {
+	parser       *Parser
+	excelFile    *excelize.File
+	streamWriter *excelize.StreamWriter
+	elemType     reflect.Type
+	tagMap       map[string]TagSetting
+	// positions the column of each cell of the row, nil if no field is bound by col or index tag
+	positions []int
+	// rowIndex 下一条记录写入的行号
+	rowIndex int
+	output   io.Writer
+}
+
+// NewStreamWriter 创建流式写入，Close时保存到fileName
+// sheetName sheet名称，为空则为结构体元素的类型+s
+// elem 记录的样例，struct或者struct指针，用于生成表头
+func (p *Parser) NewStreamWriter(fileName, sheetName string, elem interface{}) (*StreamWriter, error) {
+	return p.newStreamWriter(nil, fileName, sheetName, elem)
+}
+
+// NewStreamWriterTo 创建流式写入，Close时写入到w，fileName为逻辑文件名，仅用于错误信息
+func (p *Parser) NewStreamWriterTo(w io.Writer, fileName, sheetName string, elem interface{}) (*StreamWriter, error) {
+	return p.newStreamWriter(w, fileName, sheetName, elem)
+}
+
+func (p *Parser) newStreamWriter(w io.Writer, fileName, sheetName string, elem interface{}) (*StreamWriter, error) {
+	p.fileName = fileName
+	p.currentSheetName = sheetName
+
+	elemType := reflect.TypeOf(elem)
+	if elemType != nil && elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType == nil || elemType.Kind() != reflect.Struct {
+		return nil, NewError(fileName, sheetName, "", ErrorSliceElemType)
+	}
+
+	if len(p.currentSheetName) == 0 {
+		p.currentSheetName = fmt.Sprintf("%ss", elemType.Name())
+	}
+	tagMap, err := p.fieldTagSetting(elemType)
+	if err != nil {
+		return nil, err
+	}
+	if err = p.checkStreamFields(elemType, tagMap); err != nil {
+		return nil, err
+	}
+
+	excelFile := excelize.NewFile()
+	p.excelFile = excelFile
+	p.styles = nil
+	err = excelFile.SetSheetName(excelFile.GetSheetName(0), p.currentSheetName)
+	if err != nil {
+		_ = excelFile.Close()
+		return nil, NewError(fileName, p.currentSheetName, "", err)
+	}
+	streamWriter, err := excelFile.NewStreamWriter(p.currentSheetName)
+	if err != nil {
+		_ = excelFile.Close()
+		return nil, NewError(fileName, p.currentSheetName, "", err)
+	}
+
+	sw := &StreamWriter{
+		parser:       p,
+		excelFile:    excelFile,
+		streamWriter: streamWriter,
+		elemType:     elemType,
+		tagMap:       tagMap,
+		rowIndex:     1,
+		output:       w,
+	}
+
+	bound, err := p.boundColumnIndexes(sw.tagMap, elemType, dynamicColumns{})
+	if err != nil {
+		_ = excelFile.Close()
+		return nil, err
+	}
+	sw.positions = columnPositions(bound)
+
+	layout := sheetLayout{HeaderRowIndex: 1}
+	heads, comments := buildHead(sw.tagMap, elemType, dynamicColumns{})
+	heads, comments = placeCells(heads, sw.positions), placeCells(comments, sw.positions)
+	if err = sw.setRow(heads); err != nil {
+		_ = excelFile.Close()
+		return nil, err
+	}
+	if comments != nil {
+		layout.CommentRowIndex = sw.rowIndex
+		if err = sw.setRow(comments); err != nil {
+			_ = excelFile.Close()
+			return nil, err
+		}
+	}
+	layout.DataRowIndex = sw.rowIndex
+	if err = writeLayout(excelFile, p.currentSheetName, layout); err != nil {
+		_ = excelFile.Close()
+		return nil, NewError(fileName, p.currentSheetName, "", err)
+	}
+
+	return sw, nil
+}
+
+// checkStreamFields the columns of extra and repeat fields depend on all records, which are unknown when the
+// header is written, and the children fields need another sheet, so the stream writer does not support them
+func (p *Parser) checkStreamFields(elemType reflect.Type, tagMap map[string]TagSetting) error {
+	var errs *multierror.Error
+	for _, field := range structFields(elemType, tagMap) {
+		tagSetting := tagMap[field.Name]
+		if tagSetting.Children != "" || !tagSetting.Skip && (tagSetting.Extra || tagSetting.Repeat) {
+			errs = multierror.Append(errs,
+				newRowError(p.fileName, p.currentSheetName, 0, tagSetting.Column, ErrorFieldTypeNotSupport))
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// Add 写入一条记录，记录类型必须与创建时的elem类型一致，可以是struct或者struct指针
+func (sw *StreamWriter) Add(record interface{}) error {
+	p := sw.parser
+	rv := reflect.ValueOf(record)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || rv.Type() != sw.elemType {
+		return NewError(p.fileName, p.currentSheetName, "", ErrorRecordType)
+	}
+
+	rowData, err := p.buildRow(sw.tagMap, rv, dynamicColumns{})
+	if err != nil {
+		return err
+	}
+
+	return sw.setRow(placeCells(rowData, sw.positions))
+}
+
+func (sw *StreamWriter) setRow(values []interface{}) error {
+	p := sw.parser
+	coords, err := excelize.CoordinatesToCellName(1, sw.rowIndex)
+	if err != nil {
+		return NewError(p.fileName, p.currentSheetName, "", err)
+	}
+	if err = sw.streamWriter.SetRow(coords, values); err != nil {
+		return NewError(p.fileName, p.currentSheetName, coords, err)
+	}
+	sw.rowIndex++
+	return nil
+}
+
+// Close 刷新数据并保存到文件或者写入到writer
+func (sw *StreamWriter) Close() error {
+	p := sw.parser
+	defer func() {
+		_ = sw.excelFile.Close()
+	}()
+
+	if err := sw.streamWriter.Flush(); err != nil {
+		return NewError(p.fileName, p.currentSheetName, "", err)
+	}
+
+	var err error
+	if sw.output != nil {
+		err = sw.excelFile.Write(sw.output)
+	} else {
+		err = sw.excelFile.SaveAs(p.fileName)
+	}
+	if err != nil {
+		return NewError(p.fileName, "", "", err)
+	}
+
+	return nil
+}
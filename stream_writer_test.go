@@ -0,0 +1,114 @@
+package excelstructure
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/booyangcc/utils/convutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WriteChan(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "stream.xlsx")
+	p := NewParser()
+	records := make(chan *Person)
+	go func() {
+		defer close(records)
+		for i := 0; i < 1000; i++ {
+			records <- &Person{Name: fmt.Sprintf("name%d", i), Age: i, Man: true, Address: []string{"beijing"}}
+		}
+	}()
+	err := WriteChan(p, fileName, "persons", records)
+	assert.NoError(t, err)
+
+	// Person has comment tag, the comment row is recorded in the workbook and skipped automatically
+	persons, err := Read[*Person](p, fileName, "persons")
+	assert.NoError(t, err)
+	require.Equal(t, 1000, len(persons))
+	require.Equal(t, "name999", persons[999].Name)
+	require.Equal(t, 999, persons[999].Age)
+}
+
+type streamLevel int
+
+type StreamScore struct {
+	Name  string      `excel:"column:name"`
+	Level streamLevel `excel:"column:level"`
+}
+
+func Test_WriteChanError(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "stream.xlsx")
+	p := NewParser()
+	errLevel := errors.New("invalid level")
+	require.NoError(t, RegisterTypeConverter(p, func(value string) (streamLevel, error) {
+		return 0, nil
+	}, func(v streamLevel) (string, error) {
+		if v < 0 {
+			return "", errLevel
+		}
+		return fmt.Sprint(int(v)), nil
+	}))
+
+	records := make(chan StreamScore)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer close(records)
+		for i := 0; i < 100; i++ {
+			level := streamLevel(i)
+			if i == 10 {
+				level = -1
+			}
+			records <- StreamScore{Name: fmt.Sprintf("name%d", i), Level: level}
+		}
+	}()
+	err := WriteChan(p, fileName, "scores", records)
+	require.ErrorIs(t, err, errLevel)
+	// the remaining records are drained, the producer is not blocked
+	<-done
+}
+
+func Test_StreamWriterWithComment(t *testing.T) {
+	p := NewParser()
+	_ = p.RegisterSerializer("mySerializer", mySerializer)
+	buf := &bytes.Buffer{}
+	w, err := p.NewStreamWriterTo(buf, "download.xlsx", "", &Info{})
+	require.NoError(t, err)
+	err = w.Add(Info{Name: "booyang", Phone: convutil.String("123456789"), Age: "18", Man: true})
+	assert.NoError(t, err)
+	err = w.Add(&Info{Name: "bob", Age: "17", Man: true, Detail: Detail{Nation: "Britain"}})
+	assert.NoError(t, err)
+	err = w.Add(&Person{Name: "tom"})
+	assert.ErrorIs(t, err.(*Error).Err, ErrorRecordType)
+	require.NoError(t, w.Close())
+
+	var infos []*Info
+	err = p.ReadFromReaderWithSheetName(buf, "download.xlsx", "Infos", &infos)
+	assert.NoError(t, err)
+	require.Equal(t, 2, len(infos))
+	require.Equal(t, "123456789", *infos[0].Phone)
+	require.Nil(t, infos[1].Phone)
+	require.Equal(t, "Britain", infos[1].Detail.Nation)
+}
+
+func Test_NewStreamWriterDynamicColumns(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "stream.xlsx")
+	_, err := NewParser().NewStreamWriter(fileName, "", &Customer{})
+	require.ErrorIs(t, err, ErrorFieldTypeNotSupport)
+	columns := make([]string, 0, 3)
+	for _, e := range ErrorList(err) {
+		columns = append(columns, e.Column)
+	}
+	require.Equal(t, []string{"Phone {n}", "score_{n}", "Attrs"}, columns)
+}
+
+func Test_NewStreamWriterChildren(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "stream.xlsx")
+	_, err := NewParser().NewStreamWriter(fileName, "", &PurchaseOrder{})
+	require.ErrorIs(t, err, ErrorFieldTypeNotSupport)
+	require.Equal(t, "Lines", ErrorList(err)[0].Column)
+}
@@ -1,8 +1,14 @@
 package excelstructure
 
 import (
+	"fmt"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/xuri/excelize/v2"
 )
 
 const (
@@ -14,6 +20,8 @@ const (
 type TagSetting struct {
 	// column
 	Column string
+	// Aliases 列名的别名，column:user_name|用户名|User Name 第一个为列名，写入时使用
+	Aliases []string
 	// 	Type string
 	Type string
 	// 	default value
@@ -22,6 +30,55 @@ type TagSetting struct {
 	Skip    bool
 	// RegisterSerializer 注册序列化器
 	Serializer string
+	// Format time.Time字段的格式，使用go时间格式，如2006-01-02
+	Format string
+	// Optional 表头可以没有该列，没有时字段为默认值或者零值
+	Optional bool
+	// Inline 结构体或者结构体指针字段展开为多列，inline或者prefix:detail.，嵌套的结构体递归展开
+	Inline bool
+	// Prefix 展开的列名前缀，如prefix:detail.的Height字段的列名为detail.Height
+	Prefix string
+	// Extra map[string]string或者map[string]interface{}字段，读取时收集没有绑定到其他字段的列，
+	// 写入时将所有记录的key按顺序展开为额外的列，key不能与其他字段的列重名，其他类型的字段为无效标签
+	Extra bool
+	// Repeat 切片字段对应多个编号的列，如column:Phone {n};repeat，读取时按编号收集非空的列，
+	// 空单元格不收集，元素不保留列的编号，如Phone 1为空、Phone 2有值时读取为一个元素的切片，
+	// 写入时按最长的切片展开为Phone 1、Phone 2...，非切片字段或者列名不包含{n}为无效标签
+	Repeat bool
+	// Meta 读取时填充的行信息，不对应列，写入时跳过
+	// meta:row 行号，meta:sheet sheet名称，meta:file 文件名，meta:coordinates:<column> 列的单元格坐标
+	Meta string
+	// MetaColumn meta:coordinates的列名，可以是其他字段的列名或者别名，使用ColumnMapping时为映射后的列名
+	MetaColumn string
+	// Children 子表的sheet名称，结构体切片字段的元素读写在子表中，不对应列，如children:Lines;fk:order_no，
+	// 必须设置fk，其他类型的字段为无效标签
+	Children string
+	// ForeignKey 子表中关联主表的列
+	ForeignKey string
+	// PrimaryKey 主表中被关联的列，默认与ForeignKey相同
+	PrimaryKey string
+	// ColIndex 绑定的列位置，从1开始，col:C或者index:3，读取时不按表头文本匹配，写入时写在该列，0为不绑定
+	ColIndex int
+
+	// 校验规则，读取时校验，收集所有不满足的规则
+	// Required 单元格不能为空
+	Required bool
+	// Min Max 数值类型字段为数值的范围，其他类型为字符数的范围
+	Min string
+	Max string
+	// Len 字符数
+	Len string
+	// Regex 正则表达式，不能包含分号
+	Regex string
+	// OneOf 可选值，以|分隔，如oneof:male|female
+	OneOf []string
+	Email bool
+	URL   bool
+
+	// regex 解析标签时编译的Regex
+	regex *regexp.Regexp
+	// repeatPattern 匹配repeat字段列名模板的正则，解析标签和映射列名时编译
+	repeatPattern *regexp.Regexp
 }
 
 func parseTagSetting(str, sep, kvSep string) map[string]string {
@@ -29,7 +86,7 @@ func parseTagSetting(str, sep, kvSep string) map[string]string {
 	names := strings.Split(str, sep)
 
 	for i := 0; i < len(names); i++ {
-		values := strings.Split(names[i], kvSep)
+		values := strings.SplitN(names[i], kvSep, 2)
 		k := strings.TrimSpace(strings.ToLower(values[0]))
 
 		if len(values) >= 2 {
@@ -42,13 +99,33 @@ func parseTagSetting(str, sep, kvSep string) map[string]string {
 	return settings
 }
 
-func parseFieldTagSetting(sliceElemType reflect.Type) map[string]TagSetting {
+// parseFieldTagSetting parse the tag settings of the struct fields, key is the field name.
+// the fields of inline structs are flattened recursively, key is the field path such as Detail.Height.
+// every invalid tag setting is returned as ErrorTagInvalid
+func parseFieldTagSetting(sliceElemType reflect.Type) (map[string]TagSetting, error) {
 	tagFieldMap := make(map[string]TagSetting)
+	errs := parseStructTagSetting(sliceElemType, "", "", tagFieldMap, map[reflect.Type]bool{sliceElemType: true})
+	resolveMetaColumns(sliceElemType, tagFieldMap)
+	return tagFieldMap, errs
+}
+
+// tagError the error of the invalid tag setting of the field, param is the setting such as col:C1
+func tagError(field, param string) error {
+	e := NewError("", "", "", fmt.Errorf("%w %s", ErrorTagInvalid, param)).(*Error)
+	e.Field, e.Param = field, param
+	return e
+}
 
-	for i := 0; i < sliceElemType.NumField(); i++ {
-		field := sliceElemType.Field(i)
+// parseStructTagSetting parse the fields of structType with the field path and column prefix of the parent,
+// visiting records the struct types on the way to stop recursive inline
+func parseStructTagSetting(
+	structType reflect.Type, path, prefix string, tagFieldMap map[string]TagSetting, visiting map[reflect.Type]bool,
+) (errs error) {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
 		tag := field.Tag.Get(TagName)
-		if _, ok := tagFieldMap[field.Name]; ok {
+		name := path + field.Name
+		if _, ok := tagFieldMap[name]; ok {
 			continue
 		}
 		kvm := parseTagSetting(tag, ";", ":")
@@ -59,9 +136,110 @@ func parseFieldTagSetting(sliceElemType reflect.Type) map[string]TagSetting {
 			Comment:    kvm["comment"],
 			Skip:       kvm["skip"] == "skip",
 			Serializer: kvm["serializer"],
+			Format:     kvm["format"],
+			Optional:   kvm["optional"] == "optional",
+			Required:   kvm["required"] == "required",
+			Min:        strings.TrimSpace(kvm["min"]),
+			Max:        strings.TrimSpace(kvm["max"]),
+			Len:        strings.TrimSpace(kvm["len"]),
+			Regex:      kvm["regex"],
+			Email:      kvm["email"] == "email",
+			URL:        kvm["url"] == "url",
+		}
+		if oneOf := kvm["oneof"]; oneOf != "" {
+			tagField.OneOf = strings.Split(oneOf, "|")
+		}
+		if err := parseValidateRules(name, &tagField); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+		if col := strings.TrimSpace(kvm["col"]); col != "" {
+			colIndex, err := excelize.ColumnNameToNumber(col)
+			if err != nil {
+				errs = multierror.Append(errs, tagError(name, "col:"+col))
+				colIndex = 0
+			}
+			tagField.ColIndex = colIndex
+		} else if index := strings.TrimSpace(kvm["index"]); index != "" {
+			colIndex, err := strconv.Atoi(index)
+			if err != nil || colIndex < 1 {
+				errs = multierror.Append(errs, tagError(name, "index:"+index))
+				colIndex = 0
+			}
+			tagField.ColIndex = colIndex
+		}
+		if names := strings.Split(tagField.Column, "|"); len(names) > 1 {
+			tagField.Column, tagField.Aliases = names[0], names[1:]
+		}
+		if tagField.Column == "" {
+			tagField.Column = field.Name
+		}
+		if tagField.Column == "-" {
+			tagField.Skip = true
+		}
+
+		if kvm["extra"] == "extra" {
+			if isExtraType(field.Type) {
+				tagField.Extra = true
+			} else {
+				errs = multierror.Append(errs, tagError(name, "extra"))
+			}
+		}
+		if children := strings.TrimSpace(kvm["children"]); children != "" {
+			// 子表必须是结构体切片并且通过外键关联
+			if isChildrenType(field.Type) && strings.TrimSpace(kvm["fk"]) != "" {
+				tagField.Children = children
+				tagField.ForeignKey = strings.TrimSpace(kvm["fk"])
+				tagField.PrimaryKey = strings.TrimSpace(kvm["pk"])
+				if tagField.PrimaryKey == "" {
+					tagField.PrimaryKey = tagField.ForeignKey
+				}
+			} else {
+				errs = multierror.Append(errs, tagError(name, "children:"+children))
+			}
+			tagField.Skip = true
+		}
+		if meta := strings.TrimSpace(kvm["meta"]); meta != "" {
+			values := strings.SplitN(meta, ":", 2)
+			tagField.Meta = strings.ToLower(strings.TrimSpace(values[0]))
+			if len(values) == 2 {
+				tagField.MetaColumn = strings.TrimSpace(values[1])
+			}
+			// 只有coordinates需要列名
+			if !isMetaType(tagField.Meta, field.Type) || (tagField.Meta == MetaCoordinates) != (tagField.MetaColumn != "") {
+				errs = multierror.Append(errs, tagError(name, "meta:"+meta))
+			}
+			tagField.Skip = true
 		}
-		tagFieldMap[field.Name] = tagField
-	}
 
-	return tagFieldMap
+		// 内联的结构体字段展开为多列，列名加上前缀
+		fieldType := indirectType(field.Type)
+		_, hasPrefix := kvm["prefix"]
+		if (kvm["inline"] == "inline" || hasPrefix) && !tagField.Skip && field.PkgPath == "" &&
+			fieldType.Kind() == reflect.Struct && !isTimeType(fieldType) && !visiting[fieldType] {
+			tagField.Inline, tagField.Prefix = true, kvm["prefix"]
+			tagFieldMap[name] = tagField
+			visiting[fieldType] = true
+			if err := parseStructTagSetting(fieldType, name+".", prefix+tagField.Prefix, tagFieldMap, visiting); err != nil {
+				errs = multierror.Append(errs, err)
+			}
+			delete(visiting, fieldType)
+			continue
+		}
+
+		if prefix != "" {
+			tagField.Column = prefix + tagField.Column
+			for j := range tagField.Aliases {
+				tagField.Aliases[j] = prefix + tagField.Aliases[j]
+			}
+		}
+		if kvm["repeat"] == "repeat" {
+			if field.Type.Kind() == reflect.Slice && isRepeatColumn(tagField.Column) {
+				tagField.Repeat, tagField.repeatPattern = true, repeatPattern(tagField.Column)
+			} else {
+				errs = multierror.Append(errs, tagError(name, "repeat"))
+			}
+		}
+		tagFieldMap[name] = tagField
+	}
+	return errs
 }
@@ -0,0 +1,103 @@
+package excelstructure
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"reflect"
+	"strings"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	scannerType         = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType          = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// isTextUnmarshalType the pointer of t implements encoding.TextUnmarshaler or sql.Scanner, pointer is dereferenced by caller
+func isTextUnmarshalType(t reflect.Type) bool {
+	ptrType := reflect.PtrTo(t)
+	return ptrType.Implements(textUnmarshalerType) || ptrType.Implements(scannerType)
+}
+
+// isTextMarshalType t or the pointer of t implements encoding.TextMarshaler or driver.Valuer
+func isTextMarshalType(t reflect.Type) bool {
+	ptrType := reflect.PtrTo(t)
+	return ptrType.Implements(textMarshalerType) || ptrType.Implements(valuerType)
+}
+
+// fieldSetText set the field by encoding.TextUnmarshaler first, then sql.Scanner
+func (p *Parser) fieldSetText(field reflect.Value, cell *Cell, defaultValue string) error {
+	if !field.CanSet() {
+		return NewError(p.fileName, p.currentSheetName, cell.Coordinates, ErrorFieldNotSet)
+	}
+
+	value := cell.Value
+	if strings.TrimSpace(value) == "" {
+		value = defaultValue
+	}
+	if value == "" {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	fieldType := field.Type()
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	newField := reflect.New(fieldType)
+	var err error
+	switch v := newField.Interface().(type) {
+	case encoding.TextUnmarshaler:
+		err = v.UnmarshalText([]byte(value))
+	case sql.Scanner:
+		err = v.Scan(value)
+	}
+	if err != nil {
+		return NewError(p.fileName, p.currentSheetName, cell.Coordinates, err)
+	}
+
+	if field.Kind() == reflect.Ptr {
+		field.Set(newField)
+	} else {
+		field.Set(newField.Elem())
+	}
+	return nil
+}
+
+// textCellValue the excel cell value of the field by encoding.TextMarshaler first, then driver.Valuer
+func textCellValue(field reflect.Value, tagSetting TagSetting) (interface{}, error) {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return nil, nil
+		}
+		field = field.Elem()
+	}
+	if field.IsZero() && tagSetting.Default != "" {
+		return tagSetting.Default, nil
+	}
+
+	// value receiver methods are also in the method set of the pointer
+	ptr := reflect.New(field.Type())
+	ptr.Elem().Set(field)
+	switch v := ptr.Interface().(type) {
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return string(text), nil
+	case driver.Valuer:
+		value, err := v.Value()
+		if err != nil {
+			return nil, err
+		}
+		if b, ok := value.([]byte); ok {
+			return string(b), nil
+		}
+		return value, nil
+	}
+	return nil, nil
+}
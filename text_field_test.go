@@ -0,0 +1,111 @@
+package excelstructure
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+type OrderStatus int
+
+const (
+	OrderStatusCreated OrderStatus = iota
+	OrderStatusPaid
+)
+
+var orderStatusNames = []string{"created", "paid"}
+
+func (s OrderStatus) MarshalText() ([]byte, error) {
+	return []byte(orderStatusNames[s]), nil
+}
+
+func (s *OrderStatus) UnmarshalText(text []byte) error {
+	for i, name := range orderStatusNames {
+		if name == string(text) {
+			*s = OrderStatus(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid order status %s", text)
+}
+
+type Order struct {
+	No       string         `excel:"column:no"`
+	Status   OrderStatus    `excel:"column:status"`
+	Previous *OrderStatus   `excel:"column:previous"`
+	Remark   sql.NullString `excel:"column:remark"`
+	Amount   sql.NullInt64  `excel:"column:amount"`
+}
+
+func Test_WriteReadTextField(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "order.xlsx")
+	previous := OrderStatusCreated
+	orders := []Order{
+		{
+			No:       "A001",
+			Status:   OrderStatusPaid,
+			Previous: &previous,
+			Remark:   sql.NullString{String: "fast", Valid: true},
+			Amount:   sql.NullInt64{Int64: 100, Valid: true},
+		},
+		{No: "A002"},
+	}
+	p := NewParser()
+	err := p.Write(fileName, "orders", orders)
+	require.NoError(t, err)
+
+	f, err := excelize.OpenFile(fileName)
+	require.NoError(t, err)
+	rows, err := f.GetRows("orders")
+	require.NoError(t, err)
+	require.Equal(t, []string{"A001", "paid", "created", "fast", "100"}, rows[1])
+	require.Equal(t, []string{"A002", "created"}, rows[2])
+	require.NoError(t, f.Close())
+
+	var newOrders []Order
+	err = p.Read(fileName, &newOrders)
+	require.NoError(t, err)
+	require.Equal(t, orders, newOrders)
+}
+
+func Test_ReadTextFieldError(t *testing.T) {
+	fileName := newSheetFile(t, [][]string{
+		{"no", "status", "previous", "remark", "amount"},
+		{"A001", "refund"},
+	})
+
+	var orders []Order
+	err := NewParser().Read(fileName, &orders)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid order status refund")
+}
+
+type Price struct {
+	Amount   float64  `excel:"column:amount;default:1.5"`
+	Discount *float64 `excel:"column:discount"`
+	Rate     float32  `excel:"column:rate"`
+}
+
+func Test_ReadFloatField(t *testing.T) {
+	fileName := newSheetFile(t, [][]string{
+		{"amount", "discount", "rate"},
+		{"19.99", "0.1", "0.5"},
+		{"", "", ""},
+		{"abc", "", ""},
+	})
+	var prices []Price
+	err := NewParser().Read(fileName, &prices)
+	require.Error(t, err)
+	errs := ErrorList(err)
+	require.Len(t, errs, 1)
+	// float64 is parsed as a basic kind, not by the json serializer
+	require.ErrorIs(t, errs[0], ErrorFieldNotMatch)
+	require.Equal(t, "A4", errs[0].Coordinates)
+
+	discount := 0.1
+	require.Equal(t, []Price{{Amount: 19.99, Discount: &discount, Rate: 0.5}, {Amount: 1.5}}, prices)
+}
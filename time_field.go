@@ -0,0 +1,234 @@
+package excelstructure
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// DefaultTimeFormat time.Time字段未指定format标签时写入的格式
+const DefaultTimeFormat = "2006-01-02 15:04:05"
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+
+	timeFormats = []string{
+		DefaultTimeFormat,
+		"2006-01-02",
+		"2006/01/02 15:04:05",
+		"2006/01/02",
+		time.RFC3339,
+		// excelize默认的日期格式 m/d/yy h:mm
+		"1/2/06 15:04",
+		"01-02-06",
+	}
+
+	// numFmtTokens go time layout token to excel number format token, longer token first
+	numFmtTokens = [][2]string{
+		{"2006", "yyyy"},
+		{"January", "mmmm"},
+		{"Monday", "dddd"},
+		{"Jan", "mmm"},
+		{"Mon", "ddd"},
+		{".000", ".000"},
+		{"01", "mm"},
+		{"02", "dd"},
+		{"06", "yy"},
+		{"15", "hh"},
+		{"03", "hh"},
+		{"04", "mm"},
+		{"05", "ss"},
+		{"PM", "AM/PM"},
+		{"1", "m"},
+		{"2", "d"},
+		{"3", "h"},
+		{"4", "m"},
+		{"5", "s"},
+	}
+)
+
+// isTimeType time.Time or time.Duration, pointer is dereferenced by caller
+func isTimeType(t reflect.Type) bool {
+	return t == timeType || t == durationType
+}
+
+func (p *Parser) location() *time.Location {
+	if p.Location != nil {
+		return p.Location
+	}
+	return time.Local
+}
+
+// fieldSetTime set time.Time, *time.Time, time.Duration or *time.Duration field
+func (p *Parser) fieldSetTime(field reflect.Value, cell *Cell, defaultValue, format string) error {
+	if !field.CanSet() {
+		return NewError(p.fileName, p.currentSheetName, cell.Coordinates, ErrorFieldNotSet)
+	}
+
+	value := strings.TrimSpace(cell.Value)
+	if value == "" {
+		value = defaultValue
+	}
+	if value == "" {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	fieldType := field.Type()
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	var v interface{}
+	if fieldType == durationType {
+		d, ok := parseDuration(value)
+		if !ok {
+			return NewError(p.fileName, p.currentSheetName, cell.Coordinates, ErrorFieldNotMatch)
+		}
+		v = d
+	} else {
+		t, ok := p.parseTime(value, format)
+		if !ok {
+			return NewError(p.fileName, p.currentSheetName, cell.Coordinates, ErrorFieldNotMatch)
+		}
+		v = t
+	}
+
+	rv := reflect.ValueOf(v).Convert(fieldType)
+	if field.Kind() == reflect.Ptr {
+		ptr := reflect.New(fieldType)
+		ptr.Elem().Set(rv)
+		rv = ptr
+	}
+	field.Set(rv)
+	return nil
+}
+
+// parseTime parse by format tag, Parser.TimeFormats, then excel date serial number
+func (p *Parser) parseTime(value, format string) (time.Time, bool) {
+	loc := p.location()
+	formats := p.TimeFormats
+	if format != "" {
+		formats = append([]string{format}, p.TimeFormats...)
+	}
+	for _, layout := range formats {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, true
+		}
+	}
+
+	serial, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	t, err := excelize.ExcelDateToTime(serial, false)
+	if err != nil {
+		return time.Time{}, false
+	}
+	// excel date serial has no timezone, it is the wall clock of the parser location
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc), true
+}
+
+// parseDuration parse go duration like 1h30m, clock like 36:30:00, or excel time serial number in days
+func parseDuration(value string) (time.Duration, bool) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return d, true
+	}
+
+	if parts := strings.Split(value, ":"); len(parts) == 2 || len(parts) == 3 {
+		var d time.Duration
+		units := []time.Duration{time.Hour, time.Minute, time.Second}
+		for i, part := range parts {
+			n, err := strconv.ParseFloat(part, 64)
+			if err != nil {
+				return 0, false
+			}
+			d += time.Duration(n * float64(units[i]))
+		}
+		return d, true
+	}
+
+	days, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(days * float64(24*time.Hour)), true
+}
+
+// timeCellValue the excel cell value of time.Time, *time.Time, time.Duration or *time.Duration field,
+// time.Time is written as excel date cell with the number format of format tag,
+// time.Duration is written as text like 1h30m0s
+func (p *Parser) timeCellValue(field reflect.Value, tagSetting TagSetting) (interface{}, error) {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return nil, nil
+		}
+		field = field.Elem()
+	}
+	if field.IsZero() {
+		if tagSetting.Default != "" {
+			return tagSetting.Default, nil
+		}
+		return nil, nil
+	}
+
+	if field.Type() == durationType {
+		return time.Duration(field.Int()).String(), nil
+	}
+
+	format := tagSetting.Format
+	if format == "" {
+		format = DefaultTimeFormat
+	}
+	styleID, err := p.numFmtStyle(goLayoutToNumFmt(format))
+	if err != nil {
+		return nil, err
+	}
+
+	t := field.Interface().(time.Time).In(p.location())
+	// excel date cell has no timezone, write the wall clock of the parser location
+	wallClock := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return excelize.Cell{StyleID: styleID, Value: wallClock}, nil
+}
+
+// numFmtStyle create or reuse the style of the number format in the writing file
+func (p *Parser) numFmtStyle(numFmt string) (int, error) {
+	if styleID, ok := p.styles[numFmt]; ok {
+		return styleID, nil
+	}
+
+	styleID, err := p.excelFile.NewStyle(&excelize.Style{CustomNumFmt: &numFmt})
+	if err != nil {
+		return 0, err
+	}
+	if p.styles == nil {
+		p.styles = make(map[string]int)
+	}
+	p.styles[numFmt] = styleID
+	return styleID, nil
+}
+
+// goLayoutToNumFmt convert go time layout to excel number format, such as 2006-01-02 to yyyy-mm-dd
+func goLayoutToNumFmt(layout string) string {
+	var sb strings.Builder
+	for len(layout) > 0 {
+		matched := false
+		for _, token := range numFmtTokens {
+			if strings.HasPrefix(layout, token[0]) {
+				sb.WriteString(token[1])
+				layout = layout[len(token[0]):]
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			sb.WriteByte(layout[0])
+			layout = layout[1:]
+		}
+	}
+	return sb.String()
+}
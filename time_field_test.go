@@ -0,0 +1,98 @@
+package excelstructure
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+type Schedule struct {
+	Name  string         `excel:"column:name"`
+	Start time.Time      `excel:"column:start"`
+	Day   *time.Time     `excel:"column:day;format:2006/01/02"`
+	Clock time.Time      `excel:"column:clock;format:15:04"`
+	Cost  time.Duration  `excel:"column:cost"`
+	Wait  *time.Duration `excel:"column:wait"`
+	End   *time.Time     `excel:"column:end"`
+}
+
+func Test_WriteReadTime(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "time.xlsx")
+	shanghai := time.FixedZone("CST", 8*3600)
+	start := time.Date(2023, 8, 16, 12, 30, 15, 0, shanghai)
+	day := time.Date(2023, 8, 17, 0, 0, 0, 0, shanghai)
+	wait := 90 * time.Minute
+	schedules := []*Schedule{
+		{
+			Name:  "booyang",
+			Start: start,
+			Day:   &day,
+			Clock: time.Date(0, 1, 1, 9, 30, 0, 0, shanghai),
+			Cost:  36*time.Hour + 30*time.Minute,
+			Wait:  &wait,
+		},
+	}
+	p := NewParser()
+	p.Location = shanghai
+	err := p.Write(fileName, "schedules", schedules)
+	require.NoError(t, err)
+
+	// time is written as excel date cell with number format
+	f, err := excelize.OpenFile(fileName)
+	require.NoError(t, err)
+	rawValue, err := f.GetCellValue("schedules", "B2", excelize.Options{RawCellValue: true})
+	require.NoError(t, err)
+	require.Equal(t, "45154.521006944444", rawValue)
+	value, err := f.GetCellValue("schedules", "C2")
+	require.NoError(t, err)
+	require.Equal(t, "2023/08/17", value)
+	require.NoError(t, f.Close())
+
+	var newSchedules []*Schedule
+	err = p.Read(fileName, &newSchedules)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(newSchedules))
+	require.True(t, start.Equal(newSchedules[0].Start))
+	require.True(t, day.Equal(*newSchedules[0].Day))
+	require.Equal(t, 9, newSchedules[0].Clock.Hour())
+	require.Equal(t, 30, newSchedules[0].Clock.Minute())
+	require.Equal(t, 36*time.Hour+30*time.Minute, newSchedules[0].Cost)
+	require.Equal(t, wait, *newSchedules[0].Wait)
+	require.Nil(t, newSchedules[0].End)
+
+	// the same excel date read in another timezone keeps the wall clock
+	p.Location = time.UTC
+	newSchedules = nil
+	err = p.Read(fileName, &newSchedules)
+	require.NoError(t, err)
+	require.Equal(t, "2023-08-16 12:30:15 +0000 UTC", newSchedules[0].Start.String())
+}
+
+func Test_ReadTimeSerialAndDuration(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "serial.xlsx")
+	f := excelize.NewFile()
+	rows := [][]interface{}{
+		{"name", "start", "day", "clock", "cost", "wait", "end"},
+		{"booyang", 45154.5, "2023/08/17", "09:30", "1h30m", "0.5", "2023-08-18T10:00:00+08:00"},
+	}
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		require.NoError(t, f.SetSheetRow("Sheet1", cell, &row))
+	}
+	require.NoError(t, f.SaveAs(fileName))
+
+	p := NewParser()
+	p.Location = time.UTC
+	var schedules []*Schedule
+	err := p.Read(fileName, &schedules)
+	assert.NoError(t, err)
+	require.Equal(t, 1, len(schedules))
+	require.Equal(t, time.Date(2023, 8, 16, 12, 0, 0, 0, time.UTC), schedules[0].Start)
+	require.Equal(t, 90*time.Minute, schedules[0].Cost)
+	require.Equal(t, 12*time.Hour, *schedules[0].Wait)
+	require.Equal(t, "2023-08-18 02:00:00 +0000 UTC", schedules[0].End.UTC().String())
+}
@@ -0,0 +1,122 @@
+package excelstructure
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// validateCell check the cell value by the validation rules of the tag setting, return every violation.
+// value is the cell value or the default value if the cell is empty, fieldType pointer is dereferenced by caller
+func (p *Parser) validateCell(cell *Cell, value string, fieldType reflect.Type, tagSetting TagSetting) []error {
+	var errs []error
+	addError := func(err error, param string) {
+		if param != "" {
+			err = fmt.Errorf("%w %s", err, param)
+		}
+		e := NewError(p.fileName, p.currentSheetName, cell.Coordinates, err).(*Error)
+		e.Param, e.Value = param, value
+		errs = append(errs, e)
+	}
+
+	if (tagSetting.Required || p.IsCheckEmpty) && cell.IsEmpty {
+		addError(ErrorFieldValueEmpty, "")
+		return errs
+	}
+	if value == "" {
+		return errs
+	}
+
+	// 规则的参数在解析标签时已经校验
+	if tagSetting.Min != "" || tagSetting.Max != "" {
+		size, isNumber := validateSize(value, fieldType)
+		if limit, err := strconv.ParseFloat(tagSetting.Min, 64); err == nil && isNumber && size < limit {
+			addError(ErrorValidateMin, tagSetting.Min)
+		}
+		if limit, err := strconv.ParseFloat(tagSetting.Max, 64); err == nil && isNumber && size > limit {
+			addError(ErrorValidateMax, tagSetting.Max)
+		}
+	}
+
+	if tagSetting.Len != "" {
+		if length, err := strconv.Atoi(tagSetting.Len); err == nil && utf8.RuneCountInString(value) != length {
+			addError(ErrorValidateLen, tagSetting.Len)
+		}
+	}
+
+	if tagSetting.Regex != "" {
+		if tagSetting.regex != nil && !tagSetting.regex.MatchString(value) {
+			addError(ErrorValidateRegex, tagSetting.Regex)
+		}
+	}
+
+	if len(tagSetting.OneOf) > 0 {
+		matched := false
+		for _, v := range tagSetting.OneOf {
+			if v == value {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			addError(ErrorValidateOneOf, strings.Join(tagSetting.OneOf, "|"))
+		}
+	}
+
+	if tagSetting.Email {
+		if addr, err := mail.ParseAddress(value); err != nil || addr.Address != value {
+			addError(ErrorValidateEmail, "")
+		}
+	}
+
+	if tagSetting.URL {
+		if u, err := url.ParseRequestURI(value); err != nil || u.Scheme == "" || u.Host == "" {
+			addError(ErrorValidateURL, "")
+		}
+	}
+
+	return errs
+}
+
+// validateSize the number value of numeric field, or the character count of other field,
+// false if the numeric field value is not a number which is reported by the field setter
+func validateSize(value string, fieldType reflect.Type) (float64, bool) {
+	switch fieldType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		return f, err == nil
+	default:
+		return float64(utf8.RuneCountInString(value)), true
+	}
+}
+
+// parseValidateRules check the params of the validation rules of the field and compile the regex,
+// the invalid params are returned as ErrorTagInvalid instead of being ignored when reading
+func parseValidateRules(name string, tagSetting *TagSetting) error {
+	var errs *multierror.Error
+	for _, rule := range []struct{ key, value string }{{"min", tagSetting.Min}, {"max", tagSetting.Max}} {
+		if _, err := strconv.ParseFloat(rule.value, 64); rule.value != "" && err != nil {
+			errs = multierror.Append(errs, tagError(name, rule.key+":"+rule.value))
+		}
+	}
+	if length, err := strconv.Atoi(tagSetting.Len); tagSetting.Len != "" && (err != nil || length < 0) {
+		errs = multierror.Append(errs, tagError(name, "len:"+tagSetting.Len))
+	}
+	if tagSetting.Regex != "" {
+		re, err := regexp.Compile(tagSetting.Regex)
+		if err != nil {
+			errs = multierror.Append(errs, tagError(name, "regex:"+tagSetting.Regex))
+		}
+		tagSetting.regex = re
+	}
+	return errs.ErrorOrNil()
+}
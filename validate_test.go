@@ -0,0 +1,80 @@
+package excelstructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type Member struct {
+	Name    string `excel:"column:name;required;min:2;max:10"`
+	Age     int    `excel:"column:age;min:18;max:60"`
+	Phone   string `excel:"column:phone;len:11;regex:^1[0-9]+$"`
+	Gender  string `excel:"column:gender;oneof:male|female"`
+	Email   string `excel:"column:email;email"`
+	Website string `excel:"column:website;url"`
+	Level   int    `excel:"column:level;default:1;min:1"`
+}
+
+// newMemberFile the first member is valid, the others have violations
+func newMemberFile(t *testing.T) string {
+	return newSheetFile(t, [][]string{
+		{"name", "age", "phone", "gender", "email", "website", "level"},
+		{"booyang", "20", "13800000000", "male", "boo@example.com", "https://example.com", ""},
+		{"", "10", "2380000000", "man", "boo", "example.com", "0"},
+		{"b", "61", "1380000000a", "female", "", "", "abc"},
+	})
+}
+
+func Test_ReadWithValidation(t *testing.T) {
+	fileName := newMemberFile(t)
+
+	var members []Member
+	err := NewParser().Read(fileName, &members)
+	require.Error(t, err)
+	require.Equal(t, []Member{{
+		Name: "booyang", Age: 20, Phone: "13800000000", Gender: "male",
+		Email: "boo@example.com", Website: "https://example.com", Level: 1,
+	}}, members)
+
+	report, ok := err.(*Report)
+	require.True(t, ok)
+	var coordinates []string
+	for _, item := range report.Items {
+		coordinates = append(coordinates, item.Coordinates)
+	}
+	// every violation of every cell is collected, not only the first one
+	require.Equal(t, []string{
+		"A3", "B3", "C3", "C3", "D3", "E3", "F3", "G3",
+		"A4", "B4", "C4", "G4",
+	}, coordinates)
+	require.Contains(t, report.Items[0].Err.Error(), ErrorFieldValueEmpty.Error())
+	require.Contains(t, report.Items[1].Err.Error(), "value less than min 18")
+	require.Contains(t, report.Items[3].Err.Error(), "value not match regex ^1[0-9]+$")
+	require.Contains(t, report.Items[8].Err.Error(), "value less than min 2")
+	require.Contains(t, report.Items[11].Err.Error(), ErrorFieldNotMatch.Error())
+}
+
+type Coupon struct {
+	Code   string `excel:"column:code;regex:^[A-Z+$"`
+	Amount int    `excel:"column:amount;min:abc;max:100"`
+	Batch  string `excel:"column:batch;len:x"`
+}
+
+func Test_ValidateRuleInvalid(t *testing.T) {
+	fileName := newSheetFile(t, [][]string{
+		{"code", "amount", "batch"},
+		{"A1", "10", "b1"},
+		{"A2", "20", "b2"},
+	})
+	var coupons []Coupon
+	err := NewParser().Read(fileName, &coupons)
+	require.ErrorIs(t, err, ErrorTagInvalid)
+	// the invalid rules are reported once instead of on every row
+	errs := ErrorList(err)
+	params := make([]string, 0, len(errs))
+	for _, e := range errs {
+		params = append(params, e.Field+" "+e.Param)
+	}
+	require.Equal(t, []string{"Code regex:^[A-Z+$", "Amount min:abc", "Batch len:x"}, params)
+}
@@ -0,0 +1,145 @@
+package excelstructure
+
+import (
+	"reflect"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/xuri/excelize/v2"
+)
+
+// verticalRowIndex the logical row index of the record of the vertical sheet
+const verticalRowIndex = 1
+
+// getOutputStructType the struct type of output, false if output is not a struct pointer
+func getOutputStructType(output interface{}) (reflect.Type, bool) {
+	rv := reflect.ValueOf(output)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, false
+	}
+	return rv.Elem().Type(), true
+}
+
+// verticalSheetData the sheet data of the vertical sheet, column A is the keys and column B is the values.
+// the values are one record at the logical row verticalRowIndex, the cells keep the coordinates in the sheet
+func (p *Parser) verticalSheetData(sheetName string, rows [][]string, resolver *columnResolver) *SheetData {
+	cells := make(map[string]*Cell, len(rows))
+	sheetData := &SheetData{
+		RowTotal:      len(rows),
+		DataTotal:     verticalRowIndex,
+		SheetName:     sheetName,
+		FileName:      p.fileName,
+		Rows:          map[int]map[string]*Cell{verticalRowIndex: cells},
+		RowIndexes:    []int{verticalRowIndex},
+		keyRowIndexes: []int{},
+	}
+
+	for i, row := range rows {
+		if len(row) == 0 || row[0] == "" {
+			continue
+		}
+		rowIndex := i + 1
+		key := row[0]
+		if resolver != nil {
+			if column, ok := resolver.resolve(key); ok {
+				key = column
+			}
+		}
+
+		cell := &Cell{RowIndex: rowIndex, ColIndex: 2, Key: key, IsEmpty: true}
+		cell.Coordinates, _ = excelize.CoordinatesToCellName(2, rowIndex, p.IsCoordinatesABS)
+		if len(row) > 1 {
+			cell.Value = row[1]
+			cell.IsEmpty = p.IsEmptyFunc(row[1])
+		}
+		cells[key] = cell
+		sheetData.FieldKeys = append(sheetData.FieldKeys, key)
+		sheetData.keyRowIndexes = append(sheetData.keyRowIndexes, rowIndex)
+	}
+	return sheetData
+}
+
+// keyCoordinates the coordinates of the i-th field key, the key cell in column A of the vertical sheet,
+// or the header cell of the sheet
+func (s *SheetData) keyCoordinates(i int, abs bool) (string, error) {
+	if s.keyRowIndexes != nil {
+		return excelize.CoordinatesToCellName(1, s.keyRowIndexes[i], abs)
+	}
+	return excelize.CoordinatesToCellName(i+1, s.HeaderRowIndex, abs)
+}
+
+// readVertical read the vertical sheet to the struct pointer, the struct is not changed if there is any error
+func (p *Parser) readVertical(sheetData *SheetData, rv reflect.Value, report *Report) {
+	structType := rv.Elem().Type()
+	tagMap, err := p.fieldTagSetting(structType)
+	if err != nil {
+		report.add(sheetData.SheetName, nil, 0, err)
+		return
+	}
+	if err = p.checkNoChildren(structType, tagMap); err != nil {
+		report.add(sheetData.SheetName, nil, 0, err)
+		return
+	}
+	if p.checkHeader(sheetData, structType, tagMap, report) {
+		return
+	}
+
+	out := reflect.New(structType)
+	if err = p.parseRowToStruct(verticalRowIndex, sheetData, out, tagMap); err != nil {
+		report.add(sheetData.SheetName, sheetData.FieldKeys, 0, err)
+		return
+	}
+	rv.Elem().Set(out.Elem())
+}
+
+// writeVertical write the struct to the sheet in key-value layout, column A is the keys, column B is the values,
+// column C is the comments if any field has comment
+func (p *Parser) writeVertical(excelFile *excelize.File, rv reflect.Value) (errs error) {
+	structType := rv.Type()
+	if len(p.currentSheetName) == 0 {
+		p.currentSheetName = structType.Name()
+	}
+	tagMap, err := p.fieldTagSetting(structType)
+	if err != nil {
+		return multierror.Append(errs, err)
+	}
+	if err = p.checkNoChildren(structType, tagMap); err != nil {
+		return multierror.Append(errs, err)
+	}
+	records := reflect.Append(reflect.MakeSlice(reflect.SliceOf(structType), 0, 1), rv)
+	dynamic := buildDynamicColumns(records, tagMap)
+	if err = p.checkExtraColumns(structType, tagMap, dynamic); err != nil {
+		return multierror.Append(errs, err)
+	}
+	if err = p.newSheet(excelFile); err != nil {
+		return multierror.Append(errs, err)
+	}
+	heads, comments := buildHead(tagMap, structType, dynamic)
+	values, err := p.buildRow(tagMap, rv, dynamic)
+	if err != nil {
+		return multierror.Append(errs, err)
+	}
+
+	for i, head := range heads {
+		row := []interface{}{head, values[i]}
+		styleID := 0
+		if c, ok := values[i].(excelize.Cell); ok {
+			row[1], styleID = c.Value, c.StyleID
+		}
+		if comments != nil {
+			row = append(row, comments[i])
+		}
+
+		coords, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err = excelFile.SetSheetRow(p.currentSheetName, coords, &row); err != nil {
+			return multierror.Append(errs, NewError(p.fileName, p.currentSheetName, coords, err))
+		}
+		if styleID == 0 {
+			continue
+		}
+		cell, _ := excelize.CoordinatesToCellName(2, i+1)
+		if err = excelFile.SetCellStyle(p.currentSheetName, cell, cell, styleID); err != nil {
+			return multierror.Append(errs, NewError(p.fileName, p.currentSheetName, cell, err))
+		}
+	}
+	return nil
+}
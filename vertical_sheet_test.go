@@ -0,0 +1,123 @@
+package excelstructure
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+type AppConfig struct {
+	Name     string            `excel:"column:name|名称;required;comment:app name"`
+	Port     int               `excel:"column:port;default:8080;max:65535"`
+	Debug    bool              `excel:"column:debug"`
+	Release  time.Time         `excel:"column:release;format:2006-01-02"`
+	Hosts    []string          `excel:"column:hosts"`
+	Settings map[string]string `excel:"extra"`
+}
+
+func Test_WriteReadVertical(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "config.xlsx")
+	config := AppConfig{
+		Name:     "excelstructure",
+		Port:     9090,
+		Debug:    true,
+		Release:  time.Date(2023, 5, 1, 0, 0, 0, 0, time.Local),
+		Hosts:    []string{"a.example.com", "b.example.com"},
+		Settings: map[string]string{"timeout": "30s"},
+	}
+	p := NewParser()
+	require.NoError(t, p.Write(fileName, "config", &config))
+
+	f, err := excelize.OpenFile(fileName)
+	require.NoError(t, err)
+	rows, err := f.GetRows("config")
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		{"name", "excelstructure", "app name"},
+		{"port", "9090"},
+		{"debug", "TRUE"},
+		{"release", "2023-05-01"},
+		{"hosts", `["a.example.com","b.example.com"]`},
+		{"timeout", "30s"},
+	}, rows)
+	require.NoError(t, f.Close())
+
+	var newConfig AppConfig
+	require.NoError(t, p.ReadWithSheetName(fileName, "config", &newConfig))
+	require.Equal(t, config, newConfig)
+}
+
+func Test_ReadVertical(t *testing.T) {
+	fileName := newSheetFile(t, [][]string{
+		{"名称", "excelstructure"},
+		{"port", ""},
+		{},
+		{"debug", "yes"},
+		{"release", "2023-05-01"},
+		{"hosts", `["a.example.com"]`},
+	})
+	var config AppConfig
+	require.NoError(t, NewParser().Read(fileName, &config))
+	require.Equal(t, AppConfig{
+		Name:    "excelstructure",
+		Port:    8080,
+		Debug:   true,
+		Release: time.Date(2023, 5, 1, 0, 0, 0, 0, time.Local),
+		Hosts:   []string{"a.example.com"},
+	}, config)
+
+	fileName = newSheetFile(t, [][]string{
+		{"name", ""},
+		{"port", "70000"},
+		{"debug", "yes"},
+		{"port", "80"},
+	})
+	err := NewParser().Read(fileName, &config)
+	require.Error(t, err)
+	var coordinates []string
+	for _, e := range ErrorList(err) {
+		coordinates = append(coordinates, e.Coordinates)
+	}
+	// the missing keys and the repeated key are reported before decoding
+	require.Equal(t, ErrorCodeColumnMissing, ErrorList(err)[0].Code)
+	require.Contains(t, coordinates, "A4")
+
+	fileName = newSheetFile(t, [][]string{
+		{"name", ""},
+		{"port", "70000"},
+		{"debug", "yes"},
+		{"release", "2023-05-01"},
+		{"hosts", "[]"},
+	})
+	err = NewParser().Read(fileName, &config)
+	require.Error(t, err)
+	errs := ErrorList(err)
+	require.Len(t, errs, 2)
+	require.Equal(t, "B1", errs[0].Coordinates)
+	require.Equal(t, ErrorCodeRequired, errs[0].Code)
+	require.Equal(t, "B2", errs[1].Coordinates)
+	require.Equal(t, 2, errs[1].RowIndex)
+	require.Equal(t, ErrorCodeMax, errs[1].Code)
+	// the struct is not changed if there is any error
+	require.Equal(t, "excelstructure", config.Name)
+}
+
+type StoreConfig struct {
+	Name  string          `excel:"column:name"`
+	Lines []*PurchaseLine `excel:"children:Lines;fk:name"`
+}
+
+func Test_VerticalChildrenNotSupport(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "store.xlsx")
+	config := StoreConfig{Name: "booyang", Lines: []*PurchaseLine{{Sku: "apple", Quantity: 1}}}
+	err := NewParser().Write(fileName, "config", &config)
+	require.ErrorIs(t, err, ErrorFieldTypeNotSupport)
+	require.Equal(t, "Lines", ErrorList(err)[0].Column)
+
+	var newConfig StoreConfig
+	err = NewParser().Read(newSheetFile(t, [][]string{{"name", "booyang"}}), &newConfig)
+	require.ErrorIs(t, err, ErrorFieldTypeNotSupport)
+}
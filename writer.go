@@ -2,6 +2,7 @@ package excelstructure
 
 import (
 	"fmt"
+	"io"
 	"reflect"
 
 	"github.com/hashicorp/go-multierror"
@@ -10,15 +11,15 @@ import (
 
 // Write  写入单个sheet
 // sheetName sheet名称，为空则为结构体元素的类型+s
-// input必须是slice，slice的元素必须是struct
+// input必须是slice，slice的元素必须是struct，或者是struct及其指针，按A列为键B列为值的纵向布局写入
 func (p *Parser) Write(fileName, sheetName string, input interface{}) error {
 	return p.WriteWithMultiSheet(fileName, map[string]interface{}{
-		"": input,
+		sheetName: input,
 	})
 }
 
 // WriteWithSheetName  写入单个sheet
-// input必须是slice，slice的元素必须是struct
+// input必须是slice，slice的元素必须是struct，或者是struct及其指针，按A列为键B列为值的纵向布局写入
 func (p *Parser) WriteWithSheetName(fileName, sheetName string, input interface{}) error {
 	return p.WriteWithMultiSheet(fileName, map[string]interface{}{
 		sheetName: input,
@@ -27,32 +28,70 @@ func (p *Parser) WriteWithSheetName(fileName, sheetName string, input interface{
 
 // WriteWithMultiSheet 写入多个结构体到多个sheet，key为sheetName，value为slice
 func (p *Parser) WriteWithMultiSheet(fileName string, inputMap map[string]interface{}) error {
+	excelFile, err := p.buildFile(fileName, inputMap)
+	if err != nil {
+		return err
+	}
+
+	if err = excelFile.SaveAs(p.fileName); err != nil {
+		return NewError(p.fileName, "", "", err)
+	}
+
+	return nil
+}
+
+// WriteToWriter 写入单个sheet到writer，如http响应
+// fileName 为逻辑文件名，仅用于错误信息
+func (p *Parser) WriteToWriter(w io.Writer, fileName, sheetName string, input interface{}) error {
+	return p.WriteToWriterWithMultiSheet(w, fileName, map[string]interface{}{
+		sheetName: input,
+	})
+}
+
+// WriteToWriterWithMultiSheet 写入多个sheet到writer，key为sheetName，value为slice
+// fileName 为逻辑文件名，仅用于错误信息
+func (p *Parser) WriteToWriterWithMultiSheet(w io.Writer, fileName string, inputMap map[string]interface{}) error {
+	excelFile, err := p.buildFile(fileName, inputMap)
+	if err != nil {
+		return err
+	}
+
+	if err = excelFile.Write(w); err != nil {
+		return NewError(p.fileName, "", "", err)
+	}
+
+	return nil
+}
+
+func (p *Parser) buildFile(fileName string, inputMap map[string]interface{}) (*excelize.File, error) {
 	excelFile := excelize.NewFile()
 	p.fileName = fileName
+	p.excelFile = excelFile
+	p.styles = nil
+	p.sheets = make(map[string]struct{}, len(inputMap))
 
 	for sheetName, input := range inputMap {
 		p.currentSheetName = sheetName
 		// 返回的错误是多个错误的集合，已经是封装过的故直接返回
 		err := p.writeToSheet(excelFile, input)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 	sheetList := excelFile.GetSheetList()
 	err := excelFile.DeleteSheet(sheetList[0])
 	if err != nil {
-		return NewError(p.fileName, "", "", err)
-	}
-
-	if err = excelFile.SaveAs(p.fileName); err != nil {
-		return NewError(p.fileName, "", "", err)
+		return nil, NewError(p.fileName, "", "", err)
 	}
 
-	return nil
+	return excelFile, nil
 }
 
 func (p *Parser) writeToSheet(excelFile *excelize.File, input interface{}) (errs error) {
 	rv := reflect.Indirect(reflect.ValueOf(input))
+	if rv.Kind() == reflect.Struct {
+		return p.writeVertical(excelFile, rv)
+	}
 	if rv.Kind() != reflect.Slice {
 		errs = multierror.Append(errs,
 			NewError(p.fileName, p.currentSheetName, "", ErrorInOutputType))
@@ -69,108 +108,294 @@ func (p *Parser) writeToSheet(excelFile *excelize.File, input interface{}) (errs
 		p.currentSheetName = fmt.Sprintf("%ss", sliceElemStructType.Name())
 	}
 
-	_, err = excelFile.NewSheet(p.currentSheetName)
+	return p.writeRecords(excelFile, rv, sliceElemStructType, nil)
+}
+
+// writeRecords write the records to the current sheet, link is the foreign key column of the child sheet,
+// the children fields are written to the linked sheets after the records
+func (p *Parser) writeRecords(
+	excelFile *excelize.File, rv reflect.Value, sliceElemStructType reflect.Type, link *childLink,
+) (errs error) {
+	tagMap, err := p.fieldTagSetting(sliceElemStructType)
+	if err != nil {
+		return multierror.Append(errs, err)
+	}
+	dynamic := buildDynamicColumns(rv, tagMap)
+	if err = p.checkExtraColumns(sliceElemStructType, tagMap, dynamic); err != nil {
+		return multierror.Append(errs, err)
+	}
+	if err = p.newSheet(excelFile); err != nil {
+		return multierror.Append(errs, err)
+	}
+	bound, err := p.boundColumnIndexes(tagMap, sliceElemStructType, dynamic)
+	if err != nil {
+		return multierror.Append(errs, err)
+	}
+	heads, comments := buildHead(tagMap, sliceElemStructType, dynamic)
+	if link != nil {
+		heads, comments = link.applyHead(heads, comments)
+		// 插入的外键列不绑定位置
+		if link.index < 0 {
+			bound = append([]int{0}, bound...)
+		}
+	}
+	positions := columnPositions(bound)
+	heads, comments = placeCells(heads, positions), placeCells(comments, positions)
+	layout, err := p.writeHead(excelFile, heads, comments)
 	if err != nil {
 		errs = multierror.Append(errs, err)
 		return
 	}
 
-	tagMap := parseFieldTagSetting(sliceElemStructType)
-	err = p.writeHead(excelFile, tagMap, sliceElemStructType)
+	err = writeLayout(excelFile, p.currentSheetName, layout)
 	if err != nil {
-		errs = multierror.Append(errs, err)
+		errs = multierror.Append(errs, NewError(p.fileName, p.currentSheetName, "", err))
 		return
 	}
 
-	err = p.writeData(excelFile, tagMap, rv)
+	err = p.writeData(excelFile, tagMap, rv, layout.DataRowIndex, dynamic, link, positions)
 	if err != nil {
 		errs = multierror.Append(errs, err)
 		return
 	}
 
-	return
+	return p.writeChildren(excelFile, rv, sliceElemStructType, tagMap)
+}
+
+// newSheet create the current sheet, the sheet already created by another input or children field
+// returns ErrorSheetNameRepeat instead of being overwritten
+func (p *Parser) newSheet(excelFile *excelize.File) error {
+	if _, ok := p.sheets[p.currentSheetName]; ok {
+		return NewError(p.fileName, p.currentSheetName, "", ErrorSheetNameRepeat)
+	}
+	if _, err := excelFile.NewSheet(p.currentSheetName); err != nil {
+		return NewError(p.fileName, p.currentSheetName, "", err)
+	}
+	p.sheets[p.currentSheetName] = struct{}{}
+	return nil
+}
+
+// dynamicColumns the columns decided by the records, expanded from the extra map field and repeat slice fields
+type dynamicColumns struct {
+	extra []string
+	// repeats column count of the repeat fields, key is the field path
+	repeats map[string]int
 }
 
-func (p *Parser) writeData(ef *excelize.File, tagMap map[string]TagSetting, rv reflect.Value) error {
+func buildDynamicColumns(rv reflect.Value, tagMap map[string]TagSetting) dynamicColumns {
+	return dynamicColumns{
+		extra:   extraColumns(rv, tagMap),
+		repeats: repeatCounts(rv, tagMap),
+	}
+}
+
+func (p *Parser) writeData(
+	ef *excelize.File, tagMap map[string]TagSetting, rv reflect.Value, dataRowIndex int, dynamic dynamicColumns,
+	link *childLink, positions []int,
+) error {
 	for i := 0; i < rv.Len(); i++ {
-		elemValue := rv.Index(i)
-		elemType := elemValue.Type()
-		if elemType.Kind() == reflect.Ptr {
-			elemType = elemType.Elem()
-			elemValue = elemValue.Elem()
+		rowData, err := p.buildRow(tagMap, rv.Index(i), dynamic)
+		if err != nil {
+			return err
+		}
+		if link != nil {
+			rowData = link.applyRow(rowData, i)
 		}
+		rowData = placeCells(rowData, positions)
 
-		rowData := make([]interface{}, 0, elemType.NumField())
-		for j := 0; j < elemType.NumField(); j++ {
-			field := elemType.Field(j)
-			fieldTagSetting, ok := tagMap[field.Name]
-			if !ok {
-				fieldTagSetting = TagSetting{
-					Column:     field.Name,
-					Serializer: JSONSerializerName,
-				}
-			}
-			if fieldTagSetting.Column == "-" || fieldTagSetting.Skip {
-				continue
+		// 带样式的单元格先写入值再设置样式
+		styles := make(map[int]int)
+		for j, v := range rowData {
+			if c, ok := v.(excelize.Cell); ok {
+				rowData[j] = c.Value
+				styles[j+1] = c.StyleID
 			}
+		}
 
-			elemValueField := elemValue.Field(j)
-			realElemValue := elemValueField.Interface()
+		coords, err := excelize.CoordinatesToCellName(1, dataRowIndex+i)
+		if err != nil {
+			return err
+		}
+
+		err = ef.SetSheetRow(p.currentSheetName, coords, &rowData)
+		if err != nil {
+			return err
+		}
 
-			fieldType := field.Type.Kind()
-			if fieldType == reflect.Ptr {
-				fieldType = field.Type.Elem().Kind()
+		for colIndex, styleID := range styles {
+			cell, err := excelize.CoordinatesToCellName(colIndex, dataRowIndex+i)
+			if err != nil {
+				return err
 			}
-			if fieldType == reflect.Slice || fieldType == reflect.Map || fieldType == reflect.Struct ||
-				fieldType == reflect.Interface {
-				if len(fieldTagSetting.Serializer) == 0 {
-					fieldTagSetting.Serializer = JSONSerializerName
-				}
+			if err = ef.SetCellStyle(p.currentSheetName, cell, cell, styleID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
 
-				var serializer Serializer
-				var ok bool
-				if IsDefaultSerializer(fieldTagSetting.Serializer) {
-					serializer = DefaultSerializer
-				} else {
-					serializer, ok = p.serializers[fieldTagSetting.Serializer]
-					if !ok {
-						return NewError(p.fileName, p.currentSheetName, "", ErrorSerializerNotExist)
-					}
-				}
+// indirectType the elem type if t is pointer
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
 
-				v, err := serializer.Marshal(realElemValue)
-				if err != nil {
-					return NewError(p.fileName, p.currentSheetName, "", err)
-				}
-				rowData = append(rowData, v)
+// buildRow build the excel row values of a struct or struct pointer by tag setting,
+// the extra map field and repeat slice fields are expanded to the dynamic columns
+func (p *Parser) buildRow(
+	tagMap map[string]TagSetting, elemValue reflect.Value, dynamic dynamicColumns,
+) ([]interface{}, error) {
+	elemType := elemValue.Type()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+		elemValue = elemValue.Elem()
+	}
+
+	fields := structFields(elemType, tagMap)
+	rowData := make([]interface{}, 0, len(fields))
+	for _, field := range fields {
+		fieldTagSetting, ok := tagMap[field.Name]
+		if !ok {
+			fieldTagSetting = TagSetting{
+				Column:     field.Name,
+				Serializer: JSONSerializerName,
+			}
+		}
+		if fieldTagSetting.Column == "-" || fieldTagSetting.Skip {
+			continue
+		}
+
+		// 内联的结构体指针为nil时单元格为空
+		elemValueField, ok := fieldByIndex(elemValue, field.Index, false)
+		if fieldTagSetting.Extra {
+			if ok {
+				rowData = append(rowData, extraCellValues(elemValueField, dynamic.extra)...)
 			} else {
-				if elemValueField.Kind() == reflect.Ptr {
-					realElemValue = elemValueField.Elem().Interface()
-				}
-				if elemValueField.IsZero() && fieldTagSetting.Default != "" {
-					realElemValue = fieldTagSetting.Default
-				}
-				rowData = append(rowData, realElemValue)
+				rowData = append(rowData, make([]interface{}, len(dynamic.extra))...)
+			}
+			continue
+		}
+		if fieldTagSetting.Repeat {
+			if !ok {
+				rowData = append(rowData, make([]interface{}, dynamic.repeats[field.Name])...)
+				continue
+			}
+			values, err := p.repeatCellValues(elemValueField, fieldTagSetting, dynamic.repeats[field.Name])
+			if err != nil {
+				return nil, err
 			}
+			rowData = append(rowData, values...)
+			continue
+		}
+		if !ok {
+			rowData = append(rowData, nil)
+			continue
+		}
 
+		v, err := p.cellValue(elemValueField, fieldTagSetting)
+		if err != nil {
+			return nil, err
 		}
+		rowData = append(rowData, v)
+	}
+
+	return rowData, nil
+}
+
+// cellValue the excel cell value of the field by tag setting
+func (p *Parser) cellValue(elemValueField reflect.Value, fieldTagSetting TagSetting) (interface{}, error) {
+	fieldType := elemValueField.Type()
+	realElemValue := elemValueField.Interface()
 
-		coords, err := excelize.CoordinatesToCellName(1, p.DataIndexOffset+i+1)
+	isSerializer := isExplicitSerializer(fieldTagSetting.Serializer, isBasicKind(indirectType(fieldType).Kind()))
+	if converter, ok := p.converters[indirectType(fieldType)]; ok && !isSerializer {
+		v, err := converterCellValue(elemValueField, fieldTagSetting, converter)
 		if err != nil {
-			return err
+			return nil, NewError(p.fileName, p.currentSheetName, "", err)
 		}
+		return v, nil
+	}
 
-		err = ef.SetSheetRow(p.currentSheetName, coords, &rowData)
+	if isTimeType(indirectType(fieldType)) {
+		v, err := p.timeCellValue(elemValueField, fieldTagSetting)
 		if err != nil {
-			return err
+			return nil, NewError(p.fileName, p.currentSheetName, "", err)
 		}
+		return v, nil
 	}
-	return nil
+
+	if !isSerializer && isTextMarshalType(indirectType(fieldType)) {
+		v, err := textCellValue(elemValueField, fieldTagSetting)
+		if err != nil {
+			return nil, NewError(p.fileName, p.currentSheetName, "", err)
+		}
+		return v, nil
+	}
+
+	kind := indirectType(fieldType).Kind()
+	if isSerializer || kind == reflect.Slice || kind == reflect.Map || kind == reflect.Struct ||
+		kind == reflect.Interface {
+		if len(fieldTagSetting.Serializer) == 0 {
+			fieldTagSetting.Serializer = JSONSerializerName
+		}
+
+		var serializer Serializer
+		var ok bool
+		if IsDefaultSerializer(fieldTagSetting.Serializer) {
+			serializer = DefaultSerializer
+		} else {
+			serializer, ok = p.serializers[fieldTagSetting.Serializer]
+			if !ok {
+				return nil, NewError(p.fileName, p.currentSheetName, "", ErrorSerializerNotExist)
+			}
+		}
+
+		v, err := serializer.Marshal(realElemValue)
+		if err != nil {
+			return nil, NewError(p.fileName, p.currentSheetName, "", err)
+		}
+		return v, nil
+	}
+
+	if elemValueField.Kind() == reflect.Ptr {
+		realElemValue = nil
+		if !elemValueField.IsNil() {
+			realElemValue = elemValueField.Elem().Interface()
+		}
+	}
+	if elemValueField.IsZero() && fieldTagSetting.Default != "" {
+		realElemValue = fieldTagSetting.Default
+	}
+	return realElemValue, nil
+}
+
+// writeHead write the head row and comment row, return the sheet layout
+func (p *Parser) writeHead(ef *excelize.File, heads, comments []interface{}) (sheetLayout, error) {
+	layout := sheetLayout{HeaderRowIndex: 1, DataRowIndex: 2}
+
+	err := ef.SetSheetRow(p.currentSheetName, "A1", &heads)
+	if err != nil {
+		return layout, err
+	}
+	if comments != nil {
+		err = ef.SetSheetRow(p.currentSheetName, "A2", &comments)
+		if err != nil {
+			return layout, err
+		}
+		layout.CommentRowIndex = 2
+		layout.DataRowIndex = 3
+	}
+	return layout, nil
 }
 
-func (p *Parser) writeHead(ef *excelize.File, tagMap map[string]TagSetting, sliceElemType reflect.Type) error {
-	heads := make([]string, 0)
-	comments := make([]string, 0)
+// buildHead build the head row and comment row by tag setting, comments is nil if no field has comment.
+// the dynamic columns are placed at the position of the extra map field and repeat slice fields
+func buildHead(
+	tagMap map[string]TagSetting, sliceElemType reflect.Type, dynamic dynamicColumns,
+) (heads []interface{}, comments []interface{}) {
 	hasComment := false
 	for _, tag := range tagMap {
 		if tag.Comment != "" {
@@ -178,8 +403,12 @@ func (p *Parser) writeHead(ef *excelize.File, tagMap map[string]TagSetting, slic
 		}
 	}
 
-	for i := 0; i < sliceElemType.NumField(); i++ {
-		field := sliceElemType.Field(i)
+	fields := structFields(sliceElemType, tagMap)
+	heads = make([]interface{}, 0, len(fields))
+	if hasComment {
+		comments = make([]interface{}, 0, len(fields))
+	}
+	for _, field := range fields {
 		fieldTagSetting, ok := tagMap[field.Name]
 		if !ok {
 			fieldTagSetting = TagSetting{
@@ -189,29 +418,119 @@ func (p *Parser) writeHead(ef *excelize.File, tagMap map[string]TagSetting, slic
 		if fieldTagSetting.Column == "-" || fieldTagSetting.Skip {
 			continue
 		}
+		if fieldTagSetting.Extra {
+			for _, column := range dynamic.extra {
+				heads = append(heads, column)
+				if hasComment {
+					comments = append(comments, "")
+				}
+			}
+			continue
+		}
+		if fieldTagSetting.Repeat {
+			for n := 1; n <= dynamic.repeats[field.Name]; n++ {
+				heads = append(heads, repeatColumnName(fieldTagSetting.Column, n))
+				if hasComment {
+					comments = append(comments, fieldTagSetting.Comment)
+				}
+			}
+			continue
+		}
 		heads = append(heads, fieldTagSetting.Column)
 		if hasComment {
 			comments = append(comments, fieldTagSetting.Comment)
 		}
 	}
 
-	err := ef.SetSheetRow(p.currentSheetName, "A1", &heads)
-	if err != nil {
-		return err
+	return heads, comments
+}
+
+// boundColumnIndexes the column index start with 1 bound by col or index tag of each cell built by buildHead,
+// 0 if the cell is not bound. the fields bound to the same column return ErrorFieldRepeat
+func (p *Parser) boundColumnIndexes(
+	tagMap map[string]TagSetting, structType reflect.Type, dynamic dynamicColumns,
+) ([]int, error) {
+	var errs *multierror.Error
+	var bound []int
+	columns := make(map[int]struct{})
+	for _, field := range structFields(structType, tagMap) {
+		tagSetting, ok := tagMap[field.Name]
+		if !ok {
+			bound = append(bound, 0)
+			continue
+		}
+		switch {
+		case tagSetting.Column == "-" || tagSetting.Skip:
+		case tagSetting.Extra:
+			bound = append(bound, make([]int, len(dynamic.extra))...)
+		case tagSetting.Repeat:
+			bound = append(bound, make([]int, dynamic.repeats[field.Name])...)
+		default:
+			if tagSetting.ColIndex > 0 {
+				if _, ok := columns[tagSetting.ColIndex]; ok {
+					errs = multierror.Append(errs,
+						newRowError(p.fileName, p.currentSheetName, 0, tagSetting.Column, ErrorFieldRepeat))
+				}
+				columns[tagSetting.ColIndex] = struct{}{}
+			}
+			bound = append(bound, tagSetting.ColIndex)
+		}
 	}
-	if hasComment {
-		err = ef.SetSheetRow(p.currentSheetName, "A2", &comments)
-		if err != nil {
-			return err
+	return bound, errs.ErrorOrNil()
+}
+
+// columnPositions the column index start with 1 of each cell, the bound cells are placed at their columns
+// and the others fill the free columns in order, nil if no cell is bound
+func columnPositions(bound []int) []int {
+	reserved := make(map[int]bool)
+	for _, colIndex := range bound {
+		if colIndex > 0 {
+			reserved[colIndex] = true
 		}
-		p.DataIndexOffset = 2
 	}
-	p.hasComment = hasComment
-	return nil
+	if len(reserved) == 0 {
+		return nil
+	}
+
+	positions := make([]int, len(bound))
+	next := 1
+	for i, colIndex := range bound {
+		if colIndex > 0 {
+			positions[i] = colIndex
+			continue
+		}
+		for reserved[next] {
+			next++
+		}
+		positions[i] = next
+		next++
+	}
+	return positions
+}
+
+// placeCells move the cells to the positions, the gaps between the bound columns are empty
+func placeCells(values []interface{}, positions []int) []interface{} {
+	if values == nil || positions == nil {
+		return values
+	}
+	size := 0
+	for _, position := range positions {
+		if position > size {
+			size = position
+		}
+	}
+	placed := make([]interface{}, size)
+	for i, v := range values {
+		placed[positions[i]-1] = v
+	}
+	return placed
 }
 
 func getSliceElemType(fileName, currentSheetName string, rv reflect.Value) (reflect.Type, error) {
-	sliceType := rv.Type().Elem()
+	sliceType := rv.Type()
+	if sliceType.Kind() == reflect.Ptr {
+		sliceType = sliceType.Elem()
+	}
 	sliceElemType := sliceType.Elem()
 
 	if sliceElemType.Kind() == reflect.Ptr {
@@ -1,14 +1,17 @@
 package excelstructure
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/booyangcc/utils/convutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWriter_Write(t *testing.T) {
 	w := NewParser()
+	_ = w.RegisterSerializer("mySerializer", mySerializer)
 	infos := []*Info{
 		{
 			Name:    "booyang",
@@ -115,3 +118,44 @@ func Test_WriterSerializer(t *testing.T) {
 	err := w.Write("./test_excel_file/test_serializer_write.xlsx", "", persons)
 	assert.NoError(t, err)
 }
+
+func Test_WriteReadWithLayout(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "layout.xlsx")
+	w := NewParser()
+	_ = w.RegisterSerializer("mySerializer", mySerializer)
+	infos := []*Info{
+		{Name: "booyang", Phone: convutil.String("123456789"), Age: "18", Man: true},
+		{Name: "bob", Age: "17", Man: true},
+	}
+	err := w.WriteWithMultiSheet(fileName, map[string]interface{}{
+		"infos":  infos,
+		"person": []*BaseInfo{{Name: "tom", Age: "20", Man: true}},
+	})
+	assert.NoError(t, err)
+	// the writer does not change the parser offset
+	require.Equal(t, 1, w.DataIndexOffset)
+
+	// the comment row is recorded in the workbook, no need to set DataIndexOffset
+	r := NewParser()
+	_ = r.RegisterSerializer("mySerializer", mySerializer)
+	var newInfos []*Info
+	var persons []*BaseInfo
+	err = r.ReadWithMultiSheet(fileName, map[string]interface{}{
+		"infos":  &newInfos,
+		"person": &persons,
+	})
+	assert.NoError(t, err)
+	require.Equal(t, 2, len(newInfos))
+	require.Equal(t, "booyang", newInfos[0].Name)
+	require.Equal(t, 1, len(persons))
+	require.Equal(t, "tom", persons[0].Name)
+	require.Equal(t, 2, r.ExcelData.SheetNameData["infos"].DataIndexOffset)
+
+	// the layout can be ignored, then the comment row is read as data
+	r.IgnoreLayout = true
+	newInfos = nil
+	err = r.ReadWithSheetName(fileName, "infos", &newInfos)
+	assert.NoError(t, err)
+	require.Equal(t, 3, len(newInfos))
+	require.Equal(t, "person name", newInfos[0].Name)
+}